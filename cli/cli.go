@@ -0,0 +1,259 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/adityatresnobudi/parking-system/config"
+	"github.com/adityatresnobudi/parking-system/entity"
+	"github.com/adityatresnobudi/parking-system/parking"
+	"github.com/adityatresnobudi/parking-system/storage"
+)
+
+var (
+	ErrUsage        = errors.New("usage")
+	ErrGarageExists = errors.New("parking lot already set up, use --force to replace it")
+)
+
+const usage = `parking [flags] <command> [args]
+
+commands:
+  setup [--force] <lots>            set up lots, e.g. "10,20" or "A:10,B:20"
+  park [--type TYPE] [--permit P] <plate>
+                                    park a vehicle and print its ticket
+  unpark <ticket>                   release a vehicle and print its receipt
+  status [--format F] [--sort S]    print the parking lot status as text, json, csv or table,
+                                    with parked cars sorted by ticket, plate or entry
+  status --json                     same as --format json
+  history [--from T] [--to T] [q]   print transactions, optionally for a plate or ticket`
+
+const firstTicketID = 1000
+
+type Options struct {
+	DataPath    string
+	JournalPath string
+	ConfigPath  string
+	Clock       parking.Clock
+}
+
+type session struct {
+	repo    *storage.JSONFile
+	journal *storage.Journal
+	garage  *config.Garage
+	clock   parking.Clock
+}
+
+func Usage() string {
+	return usage
+}
+
+func Run(args []string, opts Options, stdout io.Writer) error {
+	if len(args) == 0 {
+		return usageError("missing command")
+	}
+
+	s, err := open(opts)
+	if err != nil {
+		return err
+	}
+
+	var res string
+	switch args[0] {
+	case "setup":
+		res, err = s.setup(args[1:])
+	case "park":
+		res, err = s.park(args[1:])
+	case "unpark":
+		res, err = s.unPark(args[1:])
+	case "status":
+		res, err = s.status(args[1:])
+	case "history":
+		res, err = s.history(args[1:])
+	default:
+		return usageError(fmt.Sprintf("unknown command %q", args[0]))
+	}
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(stdout, res)
+	return err
+}
+
+func open(opts Options) (*session, error) {
+	repo, err := storage.NewJSONFile(opts.DataPath)
+	if err != nil {
+		return nil, err
+	}
+	journal, err := storage.NewJournal(opts.JournalPath)
+	if err != nil {
+		return nil, err
+	}
+	s := &session{repo: repo, journal: journal, clock: opts.Clock}
+	if opts.ConfigPath != "" {
+		if s.garage, err = config.Load(opts.ConfigPath); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (s *session) setup(args []string) (string, error) {
+	fs := newFlagSet("setup")
+	force := fs.Bool("force", false, "replace an existing parking lot")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return "", err
+	}
+	if len(pos) != 1 {
+		return "", usageError("setup expects exactly one lot list")
+	}
+
+	saved, err := s.repo.Load()
+	if err != nil {
+		return "", err
+	}
+	if saved != nil && len(saved.Lots) > 0 && !*force {
+		return "", ErrGarageExists
+	}
+	attendant, err := parking.SetupHandler(pos[0], s.repo)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Parking lot set up with %d lots", len(attendant.Status())), nil
+}
+
+func (s *session) park(args []string) (string, error) {
+	fs := newFlagSet("park")
+	vehicleType := fs.String("type", "", "vehicle type (motorcycle/car/van/bus)")
+	permits := fs.String("permit", "", "comma separated permits (vip/disabled/monthly)")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return "", err
+	}
+	if len(pos) != 1 {
+		return "", usageError("park expects exactly one plate number")
+	}
+
+	attendant, err := s.attendant()
+	if err != nil {
+		return "", err
+	}
+	return parking.ParkPermitHandler(pos[0], *vehicleType, *permits, attendant)
+}
+
+func (s *session) unPark(args []string) (string, error) {
+	pos, err := parseArgs(newFlagSet("unpark"), args)
+	if err != nil {
+		return "", err
+	}
+	if len(pos) != 1 {
+		return "", usageError("unpark expects exactly one ticket id")
+	}
+
+	attendant, err := s.attendant()
+	if err != nil {
+		return "", err
+	}
+	return parking.UnParkHandler(pos[0], attendant)
+}
+
+func (s *session) status(args []string) (string, error) {
+	fs := newFlagSet("status")
+	asJSON := fs.Bool("json", false, "print status as JSON")
+	format := fs.String("format", parking.FormatText, "text, json, csv or table")
+	order := fs.String("sort", parking.OrderByTicket, "sort parked cars by ticket, plate or entry")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return "", err
+	}
+	if len(pos) != 0 {
+		return "", usageError("status takes no arguments")
+	}
+
+	attendant, err := s.attendant()
+	if err != nil {
+		return "", err
+	}
+	if *asJSON {
+		*format = parking.FormatJSON
+	}
+	res, err := parking.StatusFormatHandler(*format, *order, attendant)
+	if errors.Is(err, parking.ErrUnknownFormat) || errors.Is(err, parking.ErrUnknownOrder) {
+		return "", usageError(err.Error())
+	}
+	return res, err
+}
+
+func (s *session) history(args []string) (string, error) {
+	fs := newFlagSet("history")
+	from := fs.String("from", "", "only transactions at or after YYYY-MM-DD HH:MM")
+	to := fs.String("to", "", "only transactions before YYYY-MM-DD HH:MM")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return "", err
+	}
+	if len(pos) > 1 {
+		return "", usageError("history expects at most one plate number or ticket id")
+	}
+
+	attendant, err := s.attendant()
+	if err != nil {
+		return "", err
+	}
+	query := ""
+	if len(pos) == 1 {
+		query = pos[0]
+	}
+	return parking.HistoryHandler(query, *from, *to, attendant)
+}
+
+func (s *session) attendant() (*parking.Attendant, error) {
+	attendant, err := parking.RestoreHandler(s.repo)
+	switch {
+	case errors.Is(err, parking.ErrNoSavedGarage) && s.garage != nil:
+		if attendant, err = s.garage.Attendant(s.repo); err != nil {
+			return nil, err
+		}
+	case errors.Is(err, parking.ErrNoSavedGarage):
+		return nil, parking.ErrNoParkingLot
+	case err != nil:
+		return nil, err
+	case s.garage != nil:
+		s.garage.Apply(attendant)
+	}
+
+	if s.clock != nil {
+		attendant.SetClock(s.clock)
+	}
+	attendant.SetTicketIssuer(entity.NewSequentialIssuer(firstTicketID))
+	if err := attendant.ResumeAuditTrail(s.journal); err != nil {
+		return nil, err
+	}
+	return attendant, nil
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	pos := make([]string, 0)
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, usageError(err.Error())
+		}
+		if fs.NArg() == 0 {
+			return pos, nil
+		}
+		pos = append(pos, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+func usageError(reason string) error {
+	return fmt.Errorf("%w: %s", ErrUsage, reason)
+}
@@ -0,0 +1,160 @@
+package cli_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/adityatresnobudi/parking-system/cli"
+	"github.com/adityatresnobudi/parking-system/parking"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+
+	newOptions := func(t *testing.T) cli.Options {
+		dir := t.TempDir()
+		return cli.Options{DataPath: filepath.Join(dir, "parking.json"), JournalPath: filepath.Join(dir, "journal.jsonl")}
+	}
+
+	run := func(opts cli.Options, args ...string) (string, error) {
+		var out bytes.Buffer
+		err := cli.Run(args, opts, &out)
+		return out.String(), err
+	}
+
+	t.Run("should return usage error for missing or unknown command", func(t *testing.T) {
+		opts := newOptions(t)
+
+		_, err1 := run(opts)
+		_, err2 := run(opts, "fly")
+		_, err3 := run(opts, "park")
+		_, err4 := run(opts, "status", "--xml")
+
+		assert.ErrorIs(t, err1, cli.ErrUsage)
+		assert.ErrorIs(t, err2, cli.ErrUsage)
+		assert.ErrorIs(t, err3, cli.ErrUsage)
+		assert.ErrorIs(t, err4, cli.ErrUsage)
+	})
+
+	t.Run("should return usage error for unknown status format or sort", func(t *testing.T) {
+		opts := newOptions(t)
+		_, _ = run(opts, "setup", "2")
+
+		_, err1 := run(opts, "status", "--format", "xml")
+		_, err2 := run(opts, "status", "--sort", "owner")
+
+		assert.ErrorIs(t, err1, cli.ErrUsage)
+		assert.ErrorIs(t, err2, cli.ErrUsage)
+	})
+
+	t.Run("should return error when parking before setup", func(t *testing.T) {
+		out, err := run(newOptions(t), "park", "B123M")
+
+		assert.Equal(t, "", out)
+		assert.ErrorIs(t, err, parking.ErrNoParkingLot)
+	})
+
+	t.Run("should keep state between invocations", func(t *testing.T) {
+		opts := newOptions(t)
+
+		setup, _ := run(opts, "setup", "A:1,B:2")
+		parked, _ := run(opts, "park", "B123M", "--type", "van")
+		status, _ := run(opts, "status")
+		unparked, err := run(opts, "unpark", "1000")
+
+		assert.Equal(t, "Parking lot set up with 2 lots\n", setup)
+		assert.Equal(t, "Car parked with ticket id 1000 at lot #B space 1-A-01\n", parked)
+		assert.Contains(t, status, "#1000 B 123 M @ 1-A-01\n")
+		assert.Nil(t, err)
+		assert.Contains(t, unparked, "Car B 123 M succesfully unparked!\n")
+	})
+
+	t.Run("should not reissue ticket ids of earlier invocations", func(t *testing.T) {
+		opts := newOptions(t)
+		_, _ = run(opts, "setup", "1")
+		_, _ = run(opts, "park", "B123M")
+		_, _ = run(opts, "unpark", "1000")
+
+		parked, _ := run(opts, "park", "B456M")
+
+		assert.Equal(t, "Car parked with ticket id 1001 at lot #1 space 1-A-01\n", parked)
+	})
+
+	t.Run("should refuse to replace an existing setup without force", func(t *testing.T) {
+		opts := newOptions(t)
+		_, _ = run(opts, "setup", "1")
+
+		_, err := run(opts, "setup", "2")
+		out, forceErr := run(opts, "setup", "--force", "2,3")
+
+		assert.ErrorIs(t, err, cli.ErrGarageExists)
+		assert.Nil(t, forceErr)
+		assert.Equal(t, "Parking lot set up with 2 lots\n", out)
+	})
+
+	t.Run("should print status as json", func(t *testing.T) {
+		opts := newOptions(t)
+		opts.Clock = &fixedClock{now: time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)}
+		_, _ = run(opts, "setup", "A:1")
+		_, _ = run(opts, "park", "B123M")
+
+		out, err := run(opts, "status", "--json")
+
+		assert.Nil(t, err)
+		assert.JSONEq(t, `[{"lot":"A","free_space":0,"free_by_type":{"motorcycle":0,"car":0,"van":0,"bus":0},`+
+			`"parked_cars":[{"ticket_id":"1000","plate_number":"B 123 M","vehicle_type":"car","space":"1-A-01",`+
+			`"entry_time":"2024-01-01T08:00:00Z"}]}]`, out)
+	})
+
+	t.Run("should print status as csv sorted by plate", func(t *testing.T) {
+		opts := newOptions(t)
+		opts.Clock = &fixedClock{now: time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)}
+		_, _ = run(opts, "setup", "A:2")
+		_, _ = run(opts, "park", "B456M")
+		_, _ = run(opts, "park", "B123M")
+
+		out, err := run(opts, "status", "--format", "csv", "--sort", "plate")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "lot,ticket_id,plate_number,vehicle_type,space,entry_time\n"+
+			"A,1001,B 123 M,car,1-A-02,2024-01-01T08:00:00Z\n"+
+			"A,1000,B 456 M,car,1-A-01,2024-01-01T08:00:00Z\n", out)
+	})
+
+	t.Run("should print history for a plate", func(t *testing.T) {
+		opts := newOptions(t)
+		_, _ = run(opts, "setup", "2")
+		_, _ = run(opts, "park", "B123M")
+		_, _ = run(opts, "park", "B456M")
+
+		out, err := run(opts, "history", "b 456 m")
+
+		assert.Nil(t, err)
+		assert.Contains(t, out, "park #1001 B 456 M\n")
+		assert.NotContains(t, out, "B 123 M")
+	})
+
+	t.Run("should set up from config when no state file exists", func(t *testing.T) {
+		opts := newOptions(t)
+		opts.ConfigPath = filepath.Join(t.TempDir(), "garage.json")
+		_ = os.WriteFile(opts.ConfigPath, []byte(`{"style":"highest-capacity","lots":[{"id":"A","capacity":1},{"id":"B","capacity":3}]}`), 0o644)
+
+		parked, err := run(opts, "park", "B123M")
+		status, _ := run(opts, "status")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "Car parked with ticket id 1000 at lot #B space 1-A-01\n", parked)
+		assert.Contains(t, status, "Parking style: highest-capacity\n")
+	})
+}
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (fc *fixedClock) Now() time.Time {
+	return fc.now
+}
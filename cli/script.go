@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/adityatresnobudi/parking-system/entity"
+	"github.com/adityatresnobudi/parking-system/parking"
+)
+
+var scriptStart = time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+
+type scriptClock struct {
+	now time.Time
+}
+
+func (sc *scriptClock) Now() time.Time {
+	return sc.now
+}
+
+type script struct {
+	attendant *parking.Attendant
+	clock     *scriptClock
+}
+
+func RunScript(r io.Reader, w io.Writer) error {
+	s := &script{clock: &scriptClock{now: scriptStart}}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		res, err := s.exec(line)
+		if err != nil {
+			res = err.Error()
+		}
+		if _, err := fmt.Fprintf(w, "> %s\n%s\n", line, strings.TrimRight(res, "\n")); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func (s *script) exec(line string) (string, error) {
+	command, arg := line, ""
+	if i := strings.IndexByte(line, ' '); i != -1 {
+		command, arg = line[:i], strings.TrimSpace(line[i+1:])
+	}
+
+	switch command {
+	case "create_parking_lot":
+		return s.setup(arg)
+	case "park":
+		return parking.ParkHandler(arg, s.attendant)
+	case "park_vehicle":
+		vehicleType, plate := splitFirst(arg)
+		return parking.ParkVehicleHandler(plate, vehicleType, s.attendant)
+	case "leave":
+		return parking.UnParkHandler(arg, s.attendant)
+	case "lost":
+		return parking.LostTicketHandler(arg, "y", s.attendant)
+	case "status":
+		format, order := splitFirst(arg)
+		return parking.StatusFormatHandler(format, order, s.attendant)
+	case "style":
+		return parking.ChangeStyleHandler(arg, s.attendant)
+	case "history":
+		return parking.HistoryHandler(arg, "", "", s.attendant)
+	case "add_lot":
+		return parking.AddLotHandler(arg, s.attendant)
+	case "resize_lot":
+		lotID, capacity := splitFirst(arg)
+		return parking.ResizeLotHandler(lotID, capacity, s.attendant)
+	case "close_lot":
+		return parking.CloseLotHandler(arg, s.attendant)
+	case "reopen_lot":
+		return parking.ReopenLotHandler(arg, s.attendant)
+	case "advance":
+		return s.advance(arg)
+	}
+	return "", fmt.Errorf("unknown command %q", command)
+}
+
+func (s *script) setup(arg string) (string, error) {
+	attendant, err := parking.SetupHandler(arg, nil)
+	if err != nil {
+		return "", err
+	}
+	attendant.SetTicketIssuer(entity.NewSequentialIssuer(firstTicketID))
+	attendant.SetClock(s.clock)
+	s.attendant = attendant
+	return fmt.Sprintf("Created parking lot with %d lots", len(attendant.Status())), nil
+}
+
+func (s *script) advance(arg string) (string, error) {
+	d, err := time.ParseDuration(arg)
+	if err != nil || d < 0 {
+		return "", parking.ErrInvalidInput
+	}
+	s.clock.now = s.clock.now.Add(d)
+	return fmt.Sprintf("Clock advanced to %s", s.clock.now.Format("2006-01-02 15:04")), nil
+}
+
+func splitFirst(arg string) (string, string) {
+	if i := strings.IndexByte(arg, ' '); i != -1 {
+		return arg[:i], strings.TrimSpace(arg[i+1:])
+	}
+	return arg, ""
+}
@@ -0,0 +1,49 @@
+package cli_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adityatresnobudi/parking-system/cli"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+func TestRunScript(t *testing.T) {
+	scripts, _ := filepath.Glob("testdata/*.script")
+	assert.NotEmpty(t, scripts)
+
+	for _, path := range scripts {
+		name := strings.TrimSuffix(filepath.Base(path), ".script")
+		t.Run("should replay "+name+" script as in golden file", func(t *testing.T) {
+			input, err := os.ReadFile(path)
+			assert.Nil(t, err)
+			golden := strings.TrimSuffix(path, ".script") + ".golden"
+			var out bytes.Buffer
+
+			err = cli.RunScript(bytes.NewReader(input), &out)
+			if *update {
+				_ = os.WriteFile(golden, out.Bytes(), 0o644)
+			}
+			expected, _ := os.ReadFile(golden)
+
+			assert.Nil(t, err)
+			assert.Equal(t, string(expected), out.String())
+		})
+	}
+
+	t.Run("should produce the same output on every replay", func(t *testing.T) {
+		input, _ := os.ReadFile("testdata/billing.script")
+		var first, second bytes.Buffer
+
+		_ = cli.RunScript(bytes.NewReader(input), &first)
+		_ = cli.RunScript(bytes.NewReader(input), &second)
+
+		assert.Equal(t, first.String(), second.String())
+	})
+}
@@ -0,0 +1,337 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/adityatresnobudi/parking-system/entity"
+	"github.com/adityatresnobudi/parking-system/parking"
+	"github.com/adityatresnobudi/parking-system/plate"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrInvalidConfig     = errors.New("invalid garage config")
+	ErrUnsupportedFormat = errors.New("unsupported config format")
+)
+
+type Garage struct {
+	Style             string   `json:"style" yaml:"style"`
+	LostTicketPenalty *int     `json:"lost_ticket_penalty" yaml:"lost_ticket_penalty"`
+	Tariff            *Tariff  `json:"tariff" yaml:"tariff"`
+	Lots              []Lot    `json:"lots" yaml:"lots"`
+	Rules             []Rule   `json:"rules" yaml:"rules"`
+	PlateFormats      []string `json:"plate_formats" yaml:"plate_formats"`
+}
+
+// Rule picks a different parking style for the cars it matches. A car matches
+// when it satisfies every criterion that is set.
+type Rule struct {
+	Name     string   `json:"name" yaml:"name"`
+	Permit   string   `json:"permit" yaml:"permit"`
+	Plates   []string `json:"plates" yaml:"plates"`
+	Vehicles []string `json:"vehicles" yaml:"vehicles"`
+	Style    string   `json:"style" yaml:"style"`
+}
+
+type Lot struct {
+	ID           string         `json:"id" yaml:"id"`
+	Name         string         `json:"name" yaml:"name"`
+	Location     string         `json:"location" yaml:"location"`
+	Tags         []string       `json:"tags" yaml:"tags"`
+	Distance     int            `json:"distance" yaml:"distance"`
+	Capacity     int            `json:"capacity" yaml:"capacity"`
+	Level        string         `json:"level" yaml:"level"`
+	SpacesPerRow int            `json:"spaces_per_row" yaml:"spaces_per_row"`
+	Vehicles     map[string]int `json:"vehicles" yaml:"vehicles"`
+}
+
+type Tariff struct {
+	GracePeriod string `json:"grace_period" yaml:"grace_period"`
+	FirstHour   *int   `json:"first_hour" yaml:"first_hour"`
+	HourlyRate  *int   `json:"hourly_rate" yaml:"hourly_rate"`
+	DailyCap    *int   `json:"daily_cap" yaml:"daily_cap"`
+	NightRate   *int   `json:"night_rate" yaml:"night_rate"`
+	NightStart  *int   `json:"night_start" yaml:"night_start"`
+	NightEnd    *int   `json:"night_end" yaml:"night_end"`
+}
+
+func Load(path string) (*Garage, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	garage, err := Parse(data, filepath.Ext(path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return garage, nil
+}
+
+func Parse(data []byte, ext string) (*Garage, error) {
+	garage := &Garage{}
+	switch strings.ToLower(ext) {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(garage); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+		}
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(garage); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, ext)
+	}
+	if err := garage.Validate(); err != nil {
+		return nil, err
+	}
+	return garage, nil
+}
+
+func (g *Garage) Validate() error {
+	if len(g.Lots) == 0 {
+		return invalid("lots", "at least one lot is required")
+	}
+	ids := make(map[string]bool, len(g.Lots))
+	for i, l := range g.Lots {
+		field := fmt.Sprintf("lots[%d]", i)
+		if l.ID != "" {
+			if ids[l.ID] {
+				return invalid(field+".id", fmt.Sprintf("duplicate lot id %q", l.ID))
+			}
+			ids[l.ID] = true
+		}
+		if l.Capacity < 1 {
+			return invalid(field+".capacity", "must be greater than zero")
+		}
+		if l.Distance < 0 {
+			return invalid(field+".distance", "must not be negative")
+		}
+		if l.SpacesPerRow < 0 {
+			return invalid(field+".spaces_per_row", "must not be negative")
+		}
+		names := make([]string, 0, len(l.Vehicles))
+		for name := range l.Vehicles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			n := l.Vehicles[name]
+			if _, err := entity.ParseVehicleType(name); err != nil {
+				return invalid(field+".vehicles", fmt.Sprintf("unknown vehicle type %q", name))
+			}
+			if n < 0 {
+				return invalid(field+".vehicles."+name, "must not be negative")
+			}
+		}
+	}
+	if g.Style != "" {
+		if err := validateStyle("style", g.Style); err != nil {
+			return err
+		}
+	}
+	for i, r := range g.Rules {
+		if err := r.validate(fmt.Sprintf("rules[%d]", i)); err != nil {
+			return err
+		}
+	}
+	for i, name := range g.PlateFormats {
+		if _, err := plate.LookupFormat(name); err != nil {
+			return invalid(fmt.Sprintf("plate_formats[%d]", i), fmt.Sprintf("unknown plate format %q", name))
+		}
+	}
+	if g.LostTicketPenalty != nil && *g.LostTicketPenalty < 0 {
+		return invalid("lost_ticket_penalty", "must not be negative")
+	}
+	if g.Tariff != nil {
+		return g.Tariff.validate()
+	}
+	return nil
+}
+
+func (r Rule) validate(field string) error {
+	if r.Permit == "" && len(r.Plates) == 0 && len(r.Vehicles) == 0 {
+		return invalid(field, "at least one of permit, plates or vehicles is required")
+	}
+	if r.Permit != "" {
+		if _, err := entity.ParsePermit(r.Permit); err != nil {
+			return invalid(field+".permit", fmt.Sprintf("unknown permit %q", r.Permit))
+		}
+	}
+	for _, name := range r.Vehicles {
+		if _, err := entity.ParseVehicleType(name); err != nil || name == "" {
+			return invalid(field+".vehicles", fmt.Sprintf("unknown vehicle type %q", name))
+		}
+	}
+	if r.Style == "" {
+		return invalid(field+".style", "is required")
+	}
+	return validateStyle(field+".style", r.Style)
+}
+
+func validateStyle(field string, style string) error {
+	_, err := parking.NewStyle(style)
+	if errors.Is(err, parking.ErrInvalidPipeline) {
+		return invalid(field, err.Error())
+	}
+	if err != nil {
+		return invalid(field, fmt.Sprintf("unknown parking style %q, expected one of %s", style, strings.Join(parking.StyleNames(), ", ")))
+	}
+	return nil
+}
+
+func (t *Tariff) validate() error {
+	if t.GracePeriod != "" {
+		if d, err := time.ParseDuration(t.GracePeriod); err != nil || d < 0 {
+			return invalid("tariff.grace_period", fmt.Sprintf("invalid duration %q", t.GracePeriod))
+		}
+	}
+	for _, field := range []struct {
+		name  string
+		value *int
+		max   int
+	}{
+		{"first_hour", t.FirstHour, -1},
+		{"hourly_rate", t.HourlyRate, -1},
+		{"daily_cap", t.DailyCap, -1},
+		{"night_rate", t.NightRate, -1},
+		{"night_start", t.NightStart, 23},
+		{"night_end", t.NightEnd, 23},
+	} {
+		if field.value == nil {
+			continue
+		}
+		if *field.value < 0 {
+			return invalid("tariff."+field.name, "must not be negative")
+		}
+		if field.max >= 0 && *field.value > field.max {
+			return invalid("tariff."+field.name, "must be an hour between 0 and 23")
+		}
+	}
+	return nil
+}
+
+func (g *Garage) Attendant(repo parking.Repository) (*parking.Attendant, error) {
+	lots := make([]*parking.Lot, 0, len(g.Lots))
+	for _, l := range g.Lots {
+		lots = append(lots, l.build())
+	}
+
+	attendant := parking.NewAttendant(lots)
+	g.Apply(attendant)
+	if repo != nil {
+		if err := attendant.UseRepository(repo); err != nil {
+			return nil, err
+		}
+	}
+	return attendant, nil
+}
+
+func (g *Garage) Apply(attendant *parking.Attendant) {
+	if g.Style != "" {
+		style, _ := parking.NewStyle(g.Style)
+		attendant.ChangeStyle(style)
+	}
+	if g.LostTicketPenalty != nil {
+		attendant.SetLostTicketPenalty(*g.LostTicketPenalty)
+	}
+	if len(g.Rules) > 0 {
+		rules := make([]parking.SelectionRule, 0, len(g.Rules))
+		for _, r := range g.Rules {
+			rules = append(rules, r.build())
+		}
+		attendant.SetSelectionRules(rules...)
+	}
+	if len(g.PlateFormats) > 0 {
+		formats := make([]plate.Format, 0, len(g.PlateFormats))
+		for _, name := range g.PlateFormats {
+			f, _ := plate.LookupFormat(name)
+			formats = append(formats, f)
+		}
+		attendant.SetPlateFormats(formats...)
+	}
+	if g.Tariff != nil {
+		attendant.SetTariff(g.Tariff.build())
+	}
+}
+
+func (l Lot) build() *parking.Lot {
+	vehicles := parking.DefaultVehicleSlots
+	if l.Vehicles != nil {
+		vehicles = make(map[entity.VehicleType]int, len(l.Vehicles))
+		for name, n := range l.Vehicles {
+			vt, _ := entity.ParseVehicleType(name)
+			vehicles[vt] = n
+		}
+	}
+	level, perRow := l.Level, l.SpacesPerRow
+	if level == "" {
+		level = "1"
+	}
+	if perRow == 0 {
+		perRow = parking.DefaultSpacesPerRow
+	}
+
+	lot := parking.NewLotWithLayout(l.Capacity, perRow, level, vehicles)
+	lot.SetInfo(parking.LotInfo{ID: l.ID, Name: l.Name, Location: l.Location, Tags: l.Tags, Distance: l.Distance})
+	return lot
+}
+
+func (r Rule) build() parking.SelectionRule {
+	matchers := make([]parking.CarMatcher, 0, 3)
+	if r.Permit != "" {
+		permit, _ := entity.ParsePermit(r.Permit)
+		matchers = append(matchers, parking.HasPermit(permit))
+	}
+	if len(r.Plates) > 0 {
+		matchers = append(matchers, parking.PlateIn(r.Plates...))
+	}
+	if len(r.Vehicles) > 0 {
+		types := make([]entity.VehicleType, 0, len(r.Vehicles))
+		for _, name := range r.Vehicles {
+			vt, _ := entity.ParseVehicleType(name)
+			types = append(types, vt)
+		}
+		matchers = append(matchers, parking.VehicleIn(types...))
+	}
+	style, _ := parking.NewStyle(r.Style)
+	return parking.SelectionRule{Name: r.Name, Match: parking.AllOf(matchers...), Style: style}
+}
+
+func (t *Tariff) build() parking.Tariff {
+	tariff := *parking.DefaultTariff
+	if t.GracePeriod != "" {
+		tariff.GracePeriod, _ = time.ParseDuration(t.GracePeriod)
+	}
+	for _, field := range []struct {
+		value  *int
+		target *int
+	}{
+		{t.FirstHour, &tariff.FirstHour},
+		{t.HourlyRate, &tariff.HourlyRate},
+		{t.DailyCap, &tariff.DailyCap},
+		{t.NightRate, &tariff.NightRate},
+		{t.NightStart, &tariff.NightStart},
+		{t.NightEnd, &tariff.NightEnd},
+	} {
+		if field.value != nil {
+			*field.target = *field.value
+		}
+	}
+	return &tariff
+}
+
+func invalid(field string, reason string) error {
+	return fmt.Errorf("%w: %s: %s", ErrInvalidConfig, field, reason)
+}
@@ -0,0 +1,205 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/adityatresnobudi/parking-system/config"
+	"github.com/adityatresnobudi/parking-system/entity"
+	"github.com/adityatresnobudi/parking-system/parking"
+	"github.com/adityatresnobudi/parking-system/plate"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig(t *testing.T) {
+
+	t.Run("should load the same garage from yaml and json", func(t *testing.T) {
+		fromYAML, yamlErr := config.Load("testdata/garage.yaml")
+		fromJSON, jsonErr := config.Load("testdata/garage.json")
+
+		assert.Nil(t, yamlErr)
+		assert.Nil(t, jsonErr)
+		assert.Equal(t, fromYAML, fromJSON)
+	})
+
+	t.Run("should build attendant with lots, style, tariff and penalty from config", func(t *testing.T) {
+		garage, _ := config.Load("testdata/garage.yaml")
+
+		attendant, err := garage.Attendant(nil)
+		status, _ := parking.StatusHandler(attendant)
+		attendant.SetClock(&fixedClock{now: time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC)})
+		bus, _ := attendant.Park(&entity.Car{PlateNumber: "B 7 US", Type: entity.VehicleBus})
+		car, _ := attendant.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		quote, _ := attendant.FindLostTicket("T 3 ST")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "Parking Lot Status:\n"+
+			"Parking style: highest-free-space\n"+
+			"Lot #A North Wing @ Level 1 [ev, covered]: 4 spaces left (motorcycle: 4, car: 4, van: 2, bus: 1)\n"+
+			"1-A [. . . .]\n"+
+			"Lot #B Bus Bay @ Ground: 6 spaces left (van: 2, bus: 2)\n"+
+			"G-A [. . .]\n"+
+			"G-B [. . .]\n", status)
+		assert.Equal(t, "B", bus.Lot)
+		assert.Equal(t, "G-A-01", bus.Space)
+		assert.Equal(t, "A", car.Lot)
+		assert.Equal(t, 30000, quote.Penalty)
+	})
+
+	t.Run("should keep the configured row width and level when resizing a lot", func(t *testing.T) {
+		garage, _ := config.Load("testdata/garage.yaml")
+		attendant, _ := garage.Attendant(nil)
+
+		err := attendant.ResizeLot("B", 8)
+		status := attendant.Status()
+
+		assert.Nil(t, err)
+		assert.Equal(t, "G-C-08", status[1].Spaces[7].Label())
+	})
+
+	t.Run("should produce the same attendant as SetupHandler", func(t *testing.T) {
+		garage, _ := config.Parse([]byte(`{"lots":[{"id":"A","capacity":2},{"capacity":3}]}`), ".json")
+
+		fromConfig, err := garage.Attendant(nil)
+		fromSetup, _ := parking.SetupHandler("A:2,3", nil)
+		configStatus, _ := parking.StatusHandler(fromConfig)
+		setupStatus, _ := parking.StatusHandler(fromSetup)
+
+		assert.Nil(t, err)
+		assert.Equal(t, setupStatus, configStatus)
+	})
+
+	t.Run("should persist garage built from config", func(t *testing.T) {
+		garage, _ := config.Load("testdata/garage.json")
+		repo := &memoryRepository{}
+
+		_, err := garage.Attendant(repo)
+
+		assert.Nil(t, err)
+		assert.Len(t, repo.garage.Lots, 2)
+		assert.Equal(t, "Bus Bay", repo.garage.Lots[1].Name)
+	})
+
+	t.Run("should return precise validation errors", func(t *testing.T) {
+		cases := []struct {
+			config   string
+			expected string
+		}{
+			{`{}`, "invalid garage config: lots: at least one lot is required"},
+			{`{"lots":[{"capacity":0}]}`, "invalid garage config: lots[0].capacity: must be greater than zero"},
+			{`{"lots":[{"id":"A","capacity":1},{"id":"A","capacity":1}]}`, `invalid garage config: lots[1].id: duplicate lot id "A"`},
+			{`{"lots":[{"capacity":1,"vehicles":{"truck":2}}]}`, `invalid garage config: lots[0].vehicles: unknown vehicle type "truck"`},
+			{`{"lots":[{"capacity":1,"vehicles":{"car":-1}}]}`, "invalid garage config: lots[0].vehicles.car: must not be negative"},
+			{`{"style":"cheapest","lots":[{"capacity":1}]}`, `invalid garage config: style: unknown parking style "cheapest", expected one of first-available, highest-capacity, highest-free-space, ` +
+				`round-robin, lowest-occupancy, closest-to-entrance, fill-smallest-first, weighted-random`},
+			{`{"style":"rank:nearest","lots":[{"capacity":1}]}`, "invalid garage config: style: invalid selection pipeline: rank:nearest: unknown ranking"},
+			{`{"lots":[{"capacity":1}],"rules":[{"style":"first-available"}]}`, "invalid garage config: rules[0]: at least one of permit, plates or vehicles is required"},
+			{`{"lots":[{"capacity":1}],"rules":[{"permit":"gold","style":"first-available"}]}`, `invalid garage config: rules[0].permit: unknown permit "gold"`},
+			{`{"lots":[{"capacity":1}],"rules":[{"vehicles":["tank"],"style":"first-available"}]}`, `invalid garage config: rules[0].vehicles: unknown vehicle type "tank"`},
+			{`{"lots":[{"capacity":1}],"rules":[{"plates":["B 1"]}]}`, "invalid garage config: rules[0].style: is required"},
+			{`{"plate_formats":["martian"],"lots":[{"capacity":1}]}`, `invalid garage config: plate_formats[0]: unknown plate format "martian"`},
+			{`{"lots":[{"capacity":1,"distance":-5}]}`, "invalid garage config: lots[0].distance: must not be negative"},
+			{`{"tariff":{"grace_period":"soon"},"lots":[{"capacity":1}]}`, `invalid garage config: tariff.grace_period: invalid duration "soon"`},
+			{`{"tariff":{"night_start":24},"lots":[{"capacity":1}]}`, "invalid garage config: tariff.night_start: must be an hour between 0 and 23"},
+			{`{"lost_ticket_penalty":-1,"lots":[{"capacity":1}]}`, "invalid garage config: lost_ticket_penalty: must not be negative"},
+		}
+
+		for _, c := range cases {
+			garage, err := config.Parse([]byte(c.config), ".json")
+
+			assert.Nil(t, garage)
+			assert.ErrorIs(t, err, config.ErrInvalidConfig)
+			assert.EqualError(t, err, c.expected)
+		}
+	})
+
+	t.Run("should apply selection rules in order from config", func(t *testing.T) {
+		garage, err := config.Parse([]byte(`
+style: first-available
+lots:
+  - {id: A, capacity: 4, distance: 5}
+  - {id: B, capacity: 4, distance: 5, tags: [accessible]}
+  - {id: C, capacity: 6, distance: 1}
+rules:
+  - {name: disabled, permit: disabled, style: "filter:tag=accessible"}
+  - {name: vip, plates: [B 1 VIP], style: closest-to-entrance}
+  - {name: oversize, vehicles: [van], style: highest-capacity}
+`), ".yaml")
+		attendant, _ := garage.Attendant(nil)
+
+		disabled, _ := attendant.Park(&entity.Car{PlateNumber: "D 1 SBL", Permits: []entity.Permit{entity.PermitDisabled}})
+		vip, _ := attendant.Park(&entity.Car{PlateNumber: "B 1 VIP"})
+		van, _ := attendant.Park(&entity.Car{PlateNumber: "V 4 N", Type: entity.VehicleVan})
+		car, _ := attendant.Park(&entity.Car{PlateNumber: "T 3 ST"})
+
+		assert.Nil(t, err)
+		assert.Len(t, attendant.SelectionRules(), 3)
+		assert.Equal(t, "B", disabled.Lot)
+		assert.Equal(t, "C", vip.Lot)
+		assert.Equal(t, "C", van.Lot)
+		assert.Equal(t, "A", car.Lot)
+	})
+
+	t.Run("should apply plate formats from config", func(t *testing.T) {
+		garage, _ := config.Parse([]byte(`{"plate_formats":["indonesian"],"lots":[{"capacity":2}]}`), ".json")
+		attendant, _ := garage.Attendant(nil)
+
+		_, invalidErr := parking.ParkHandler("P O LE", attendant)
+		res, err := parking.ParkHandler("b123m", attendant)
+
+		assert.ErrorIs(t, invalidErr, plate.ErrInvalidPlate)
+		assert.Nil(t, err)
+		assert.Contains(t, res, "Car parked with ticket id")
+	})
+
+	t.Run("should reject unknown fields", func(t *testing.T) {
+		_, jsonErr := config.Parse([]byte(`{"lots":[{"capacity":1,"colour":"red"}]}`), ".json")
+		_, yamlErr := config.Parse([]byte("lots:\n  - capacity: 1\n    colour: red\n"), ".yaml")
+
+		assert.ErrorIs(t, jsonErr, config.ErrInvalidConfig)
+		assert.Contains(t, jsonErr.Error(), `unknown field "colour"`)
+		assert.ErrorIs(t, yamlErr, config.ErrInvalidConfig)
+		assert.Contains(t, yamlErr.Error(), "field colour not found")
+	})
+
+	t.Run("should return error for unsupported format", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "garage.toml")
+		_ = os.WriteFile(path, []byte("[[lots]]\ncapacity = 1\n"), 0o644)
+
+		garage, err := config.Load(path)
+
+		assert.Nil(t, garage)
+		assert.ErrorIs(t, err, config.ErrUnsupportedFormat)
+	})
+}
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (fc *fixedClock) Now() time.Time {
+	return fc.now
+}
+
+type memoryRepository struct {
+	garage *parking.GarageRecord
+}
+
+func (m *memoryRepository) Load() (*parking.GarageRecord, error) {
+	return m.garage, nil
+}
+
+func (m *memoryRepository) SaveGarage(garage parking.GarageRecord) error {
+	m.garage = &garage
+	return nil
+}
+
+func (m *memoryRepository) SaveTicket(lotID string, record parking.TicketRecord) error {
+	return nil
+}
+
+func (m *memoryRepository) DeleteTicket(ticketID string) error {
+	return nil
+}
@@ -1,5 +1,23 @@
 package entity
 
 type Car struct {
-	PlateNumber string
+	PlateNumber string      `json:"plate_number"`
+	Type        VehicleType `json:"type,omitempty"`
+	Permits     []Permit    `json:"permits,omitempty"`
+}
+
+func (c *Car) VehicleType() VehicleType {
+	if c.Type == "" {
+		return VehicleCar
+	}
+	return c.Type
+}
+
+func (c *Car) HasPermit(permit Permit) bool {
+	for _, p := range c.Permits {
+		if p == permit {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrUnknownPermit = errors.New("unknown permit")
+
+type Permit string
+
+const (
+	PermitVIP      Permit = "vip"
+	PermitDisabled Permit = "disabled"
+	PermitMonthly  Permit = "monthly"
+)
+
+var Permits = []Permit{PermitVIP, PermitDisabled, PermitMonthly}
+
+func ParsePermit(s string) (Permit, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for _, p := range Permits {
+		if string(p) == s {
+			return p, nil
+		}
+	}
+	return "", ErrUnknownPermit
+}
+
+// ParsePermits parses a comma separated permit list. An empty list has no
+// permits.
+func ParsePermits(s string) ([]Permit, error) {
+	output := make([]Permit, 0)
+	if strings.TrimSpace(s) == "" {
+		return output, nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		p, err := ParsePermit(part)
+		if err != nil {
+			return nil, err
+		}
+		output = append(output, p)
+	}
+	return output, nil
+}
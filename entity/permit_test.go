@@ -0,0 +1,30 @@
+package entity_test
+
+import (
+	"testing"
+
+	. "github.com/adityatresnobudi/parking-system/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePermits(t *testing.T) {
+	t.Run("should return no permits when list is empty", func(t *testing.T) {
+		permits, err := ParsePermits(" ")
+
+		assert.Nil(t, err)
+		assert.Empty(t, permits)
+	})
+
+	t.Run("should parse comma separated permits case insensitively", func(t *testing.T) {
+		permits, err := ParsePermits("VIP, monthly")
+
+		assert.Nil(t, err)
+		assert.Equal(t, []Permit{PermitVIP, PermitMonthly}, permits)
+	})
+
+	t.Run("should return error when permit is unknown", func(t *testing.T) {
+		_, err := ParsePermits("vip,gold")
+
+		assert.ErrorIs(t, err, ErrUnknownPermit)
+	})
+}
@@ -1,24 +1,20 @@
 package entity
 
-import (
-	"fmt"
-	"math/rand"
-	"time"
-)
+import "time"
 
 const (
 	minID = 1000
-	maxID = 9999
 )
 
+var DefaultTicketIssuer TicketIssuer = NewSequentialIssuer(minID)
+
 type Ticket struct {
-	ID string
+	ID        string    `json:"id"`
+	EntryTime time.Time `json:"entry_time"`
+	Lot       string    `json:"lot,omitempty"`
+	Space     string    `json:"space,omitempty"`
 }
 
 func NewTicket() Ticket {
-	rand.Seed(time.Now().UnixNano())
-	unique := rand.Intn(maxID-minID+1) + minID
-	return Ticket{
-		ID: fmt.Sprint(unique),
-	}
+	return DefaultTicketIssuer.Issue()
 }
@@ -0,0 +1,123 @@
+package entity
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+type TicketIssuer interface {
+	Issue() Ticket
+}
+
+type SequentialIssuer struct {
+	mu   sync.Mutex
+	next int
+}
+
+func NewSequentialIssuer(start int) *SequentialIssuer {
+	return &SequentialIssuer{next: start}
+}
+
+func (s *SequentialIssuer) Issue() Ticket {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := s.next
+	s.next++
+	return Ticket{ID: strconv.Itoa(id)}
+}
+
+func (s *SequentialIssuer) Observe(id string) {
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n >= s.next {
+		s.next = n + 1
+	}
+}
+
+type ULIDIssuer struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	lastMs  uint64
+	entropy [10]byte
+}
+
+func NewULIDIssuer() *ULIDIssuer {
+	return &ULIDIssuer{now: time.Now}
+}
+
+func (u *ULIDIssuer) Issue() Ticket {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	ms := uint64(u.now().UnixMilli())
+	if ms <= u.lastMs {
+		ms = u.lastMs
+		u.incrementEntropy()
+	} else {
+		u.lastMs = ms
+		if _, err := rand.Read(u.entropy[:]); err != nil {
+			panic(fmt.Sprintf("ulid: reading entropy: %v", err))
+		}
+	}
+	return Ticket{ID: u.encode(ms)}
+}
+
+func (u *ULIDIssuer) incrementEntropy() {
+	for i := len(u.entropy) - 1; i >= 0; i-- {
+		u.entropy[i]++
+		if u.entropy[i] != 0 {
+			return
+		}
+	}
+	u.lastMs++
+}
+
+func (u *ULIDIssuer) encode(ms uint64) string {
+	var raw [16]byte
+	for i := 0; i < 6; i++ {
+		raw[i] = byte(ms >> (40 - 8*i))
+	}
+	copy(raw[6:], u.entropy[:])
+
+	out := make([]byte, 26)
+	var acc uint32
+	bits, pos := 2, 0
+	for _, b := range raw {
+		acc = acc<<8 | uint32(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			out[pos] = crockford[(acc>>uint(bits))&0x1f]
+			pos++
+		}
+	}
+	return string(out)
+}
+
+type PrefixedIssuer struct {
+	prefix string
+	base   TicketIssuer
+}
+
+func NewPrefixedIssuer(prefix string, base TicketIssuer) *PrefixedIssuer {
+	return &PrefixedIssuer{prefix: prefix, base: base}
+}
+
+func (p *PrefixedIssuer) Observe(id string) {
+	if o, ok := p.base.(interface{ Observe(string) }); ok && strings.HasPrefix(id, p.prefix) {
+		o.Observe(strings.TrimPrefix(id, p.prefix))
+	}
+}
+
+func (p *PrefixedIssuer) Issue() Ticket {
+	return Ticket{ID: p.prefix + p.base.Issue().ID}
+}
@@ -0,0 +1,85 @@
+package entity_test
+
+import (
+	"sync"
+	"testing"
+
+	. "github.com/adityatresnobudi/parking-system/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSequentialIssuer(t *testing.T) {
+	t.Run("should issue increasing ticket IDs starting from given number", func(t *testing.T) {
+		issuer := NewSequentialIssuer(1000)
+
+		t1 := issuer.Issue()
+		t2 := issuer.Issue()
+
+		assert.Equal(t, "1000", t1.ID)
+		assert.Equal(t, "1001", t2.ID)
+	})
+
+	t.Run("should issue unique IDs when called from many goroutines", func(t *testing.T) {
+		issuer := NewSequentialIssuer(1)
+		ids := make(chan string, 5000)
+		var wg sync.WaitGroup
+
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					ids <- issuer.Issue().ID
+				}
+			}()
+		}
+		wg.Wait()
+		close(ids)
+
+		seen := make(map[string]bool)
+		for id := range ids {
+			assert.False(t, seen[id])
+			seen[id] = true
+		}
+		assert.Len(t, seen, 5000)
+	})
+}
+
+func TestULIDIssuer(t *testing.T) {
+	t.Run("should issue 26 character IDs", func(t *testing.T) {
+		issuer := NewULIDIssuer()
+
+		ticket := issuer.Issue()
+
+		assert.Len(t, ticket.ID, 26)
+	})
+
+	t.Run("should issue unique and sortable IDs", func(t *testing.T) {
+		issuer := NewULIDIssuer()
+		prev := issuer.Issue().ID
+
+		for i := 0; i < 5000; i++ {
+			next := issuer.Issue().ID
+			assert.Less(t, prev, next)
+			prev = next
+		}
+	})
+}
+
+func TestPrefixedIssuer(t *testing.T) {
+	t.Run("should prefix IDs issued by base issuer", func(t *testing.T) {
+		issuer := NewPrefixedIssuer("A-", NewSequentialIssuer(7))
+
+		ticket := issuer.Issue()
+
+		assert.Equal(t, "A-7", ticket.ID)
+	})
+
+	t.Run("should not collide with other prefix sharing the same base", func(t *testing.T) {
+		base := NewSequentialIssuer(1)
+		a := NewPrefixedIssuer("A-", base)
+		b := NewPrefixedIssuer("B-", base)
+
+		assert.NotEqual(t, a.Issue(), b.Issue())
+	})
+}
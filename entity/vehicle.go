@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrUnknownVehicleType = errors.New("unknown vehicle type")
+
+type VehicleType string
+
+const (
+	VehicleMotorcycle VehicleType = "motorcycle"
+	VehicleCar        VehicleType = "car"
+	VehicleVan        VehicleType = "van"
+	VehicleBus        VehicleType = "bus"
+)
+
+var VehicleTypes = []VehicleType{VehicleMotorcycle, VehicleCar, VehicleVan, VehicleBus}
+
+func ParseVehicleType(s string) (VehicleType, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return VehicleCar, nil
+	}
+	for _, vt := range VehicleTypes {
+		if string(vt) == s {
+			return vt, nil
+		}
+	}
+	return "", ErrUnknownVehicleType
+}
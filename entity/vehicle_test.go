@@ -0,0 +1,30 @@
+package entity_test
+
+import (
+	"testing"
+
+	. "github.com/adityatresnobudi/parking-system/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVehicleType(t *testing.T) {
+	t.Run("should default to car when vehicle type is empty", func(t *testing.T) {
+		vt, err := ParseVehicleType("")
+
+		assert.Nil(t, err)
+		assert.Equal(t, VehicleCar, vt)
+	})
+
+	t.Run("should parse vehicle type case insensitively", func(t *testing.T) {
+		vt, err := ParseVehicleType(" Van ")
+
+		assert.Nil(t, err)
+		assert.Equal(t, VehicleVan, vt)
+	})
+
+	t.Run("should return error when vehicle type is unknown", func(t *testing.T) {
+		_, err := ParseVehicleType("tank")
+
+		assert.ErrorIs(t, err, ErrUnknownVehicleType)
+	})
+}
@@ -2,10 +2,16 @@ package main
 
 import (
 	"bufio"
+	"errors"
+	"flag"
 	"fmt"
+	"net/http"
 	"os"
 
+	"github.com/adityatresnobudi/parking-system/cli"
+	"github.com/adityatresnobudi/parking-system/config"
 	"github.com/adityatresnobudi/parking-system/parking"
+	"github.com/adityatresnobudi/parking-system/storage"
 )
 
 func promptInput(scanner *bufio.Scanner, text string) string {
@@ -22,8 +28,103 @@ func outputHandler(err error, a ...any) {
 	fmt.Println(a...)
 }
 
+func runScript(path string) error {
+	if path == "-" {
+		return cli.RunScript(os.Stdin, os.Stdout)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return cli.RunScript(f, os.Stdout)
+}
+
 func main() {
-	var attendant *parking.Attendant
+	dataPath := flag.String("data", "parking.json", "file used to persist parking lots and parked cars")
+	journalPath := flag.String("journal", "parking-journal.jsonl", "file used to append every parking transaction")
+	configPath := flag.String("config", "", "garage config file (.json, .yaml or .yml) used when no saved parking lot exists")
+	scriptPath := flag.String("script", "", "replay menu commands from this file (\"-\" for stdin) and exit")
+	httpAddr := flag.String("http", "", "serve the JSON API on this address instead of the interactive menu")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: "+cli.Usage()+"\n\nflags:")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *scriptPath != "" {
+		if err := runScript(*scriptPath); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.NArg() > 0 {
+		opts := cli.Options{DataPath: *dataPath, JournalPath: *journalPath, ConfigPath: *configPath}
+		if err := cli.Run(flag.Args(), opts, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			if errors.Is(err, cli.ErrUsage) {
+				flag.Usage()
+				os.Exit(2)
+			}
+			os.Exit(1)
+		}
+		return
+	}
+
+	repo, err := storage.NewJSONFile(*dataPath)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	journal, err := storage.NewJournal(*journalPath)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	var garage *config.Garage
+	if *configPath != "" {
+		if garage, err = config.Load(*configPath); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+	}
+	attendant, err := parking.RestoreHandler(repo)
+	if err == nil {
+		fmt.Printf("restored parking lot from %s\n", *dataPath)
+		if garage != nil {
+			garage.Apply(attendant)
+		}
+	} else if garage != nil {
+		if attendant, err = garage.Attendant(repo); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		fmt.Printf("parking lot set up from %s\n", *configPath)
+	}
+	configure := func(a *parking.Attendant) error {
+		if garage != nil {
+			garage.Apply(a)
+		}
+		return a.ResumeAuditTrail(journal)
+	}
+	if attendant != nil {
+		if err := attendant.ResumeAuditTrail(journal); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+	}
+
+	if *httpAddr != "" {
+		fmt.Printf("serving parking API on %s\n", *httpAddr)
+		if err := http.ListenAndServe(*httpAddr, parking.NewHTTPServer(attendant, repo, configure)); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
 	separator := "-------------------"
 	scanner := bufio.NewScanner(os.Stdin)
 	exit := false
@@ -32,7 +133,15 @@ func main() {
 		"2. Park\n" +
 		"3. Un Park\n" +
 		"4. Status\n" +
-		"5. Exit"
+		"5. Parking Style\n" +
+		"6. Lost Ticket\n" +
+		"7. Reserve\n" +
+		"8. History\n" +
+		"9. Add Lot\n" +
+		"10. Resize Lot\n" +
+		"11. Close Lot\n" +
+		"12. Reopen Lot\n" +
+		"13. Exit"
 
 	for !exit {
 		fmt.Println(separator)
@@ -41,13 +150,18 @@ func main() {
 
 		switch input {
 		case "1":
-			capacities := promptInput(scanner, "input parking lot capacities: ")
-			res, err := parking.SetupHandler(capacities)
+			capacities := promptInput(scanner, "input parking lot capacities (e.g. 10,20 or A:10,B:20): ")
+			res, err := parking.SetupHandler(capacities, repo)
+			if err == nil {
+				err = configure(res)
+			}
 			attendant = res
 			outputHandler(err)
 		case "2":
 			plateNumber := promptInput(scanner, "input plate number: ")
-			res, err := parking.ParkHandler(plateNumber, attendant)
+			vehicleType := promptInput(scanner, "input vehicle type (motorcycle/car/van/bus, default car): ")
+			permits := promptInput(scanner, "input permits (comma separated vip/disabled/monthly, default none): ")
+			res, err := parking.ParkPermitHandler(plateNumber, vehicleType, permits, attendant)
 			outputHandler(err, res)
 		case "3":
 			ticket := promptInput(scanner, "input ticket id: ")
@@ -57,6 +171,58 @@ func main() {
 			res, err := parking.StatusHandler(attendant)
 			outputHandler(err, res)
 		case "5":
+			styles, err := parking.StyleListHandler(attendant)
+			if err != nil {
+				outputHandler(err)
+				break
+			}
+			fmt.Println(styles)
+			style := promptInput(scanner, "input parking style: ")
+			res, err := parking.ChangeStyleHandler(style, attendant)
+			outputHandler(err, res)
+		case "6":
+			plateNumber := promptInput(scanner, "input plate number: ")
+			quote, err := parking.LostTicketQuoteHandler(plateNumber, attendant)
+			if err != nil {
+				outputHandler(err)
+				break
+			}
+			fmt.Println(quote)
+			confirm := promptInput(scanner, "release car with lost ticket penalty? (y/n): ")
+			res, err := parking.LostTicketHandler(plateNumber, confirm, attendant)
+			outputHandler(err, res)
+		case "7":
+			lotID := promptInput(scanner, "input lot id: ")
+			plateNumber := promptInput(scanner, "input plate number: ")
+			vehicleType := promptInput(scanner, "input vehicle type (motorcycle/car/van/bus, default car): ")
+			start := promptInput(scanner, "input arrival window start (YYYY-MM-DD HH:MM): ")
+			end := promptInput(scanner, "input arrival window end (YYYY-MM-DD HH:MM): ")
+			res, err := parking.ReserveHandler(lotID, plateNumber, vehicleType, start, end, attendant)
+			outputHandler(err, res)
+		case "8":
+			query := promptInput(scanner, "input plate number or ticket id (empty for all): ")
+			from := promptInput(scanner, "input from (YYYY-MM-DD HH:MM, empty for any): ")
+			to := promptInput(scanner, "input until (YYYY-MM-DD HH:MM, empty for any): ")
+			res, err := parking.HistoryHandler(query, from, to, attendant)
+			outputHandler(err, res)
+		case "9":
+			capacity := promptInput(scanner, "input parking lot capacity (e.g. 10 or C:10): ")
+			res, err := parking.AddLotHandler(capacity, attendant)
+			outputHandler(err, res)
+		case "10":
+			lotID := promptInput(scanner, "input lot id: ")
+			capacity := promptInput(scanner, "input new capacity: ")
+			res, err := parking.ResizeLotHandler(lotID, capacity, attendant)
+			outputHandler(err, res)
+		case "11":
+			lotID := promptInput(scanner, "input lot id: ")
+			res, err := parking.CloseLotHandler(lotID, attendant)
+			outputHandler(err, res)
+		case "12":
+			lotID := promptInput(scanner, "input lot id: ")
+			res, err := parking.ReopenLotHandler(lotID, attendant)
+			outputHandler(err, res)
+		case "13":
 			exit = true
 		default:
 			fmt.Println("invalid menu")
@@ -13,16 +13,14 @@ type LotSelector struct {
 }
 
 // SelectLot provides a mock function with given fields: lots
-func (_m *LotSelector) SelectLot(lots []*parking.Lot) *parking.Lot {
+func (_m *LotSelector) SelectLot(lots []parking.LotView) int {
 	ret := _m.Called(lots)
 
-	var r0 *parking.Lot
-	if rf, ok := ret.Get(0).(func([]*parking.Lot) *parking.Lot); ok {
+	var r0 int
+	if rf, ok := ret.Get(0).(func([]parking.LotView) int); ok {
 		r0 = rf(lots)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*parking.Lot)
-		}
+		r0 = ret.Get(0).(int)
 	}
 
 	return r0
@@ -0,0 +1,169 @@
+package parking
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrLotClosed        = errors.New("parking lot is closed")
+	ErrInvalidCapacity  = errors.New("capacity must be greater than zero")
+	ErrBelowOccupancy   = errors.New("capacity is below current occupancy")
+	ErrLotAlreadyClosed = errors.New("parking lot is already closed")
+	ErrLotNotClosed     = errors.New("parking lot is not closed")
+)
+
+// AddLot adds a lot to the attendant. On an attendant issued by a Garage the
+// lot is added to the garage and handed to every attendant of that garage that
+// was not limited to a list of lots.
+func (a *Attendant) AddLot(lot *Lot) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.garage != nil {
+		return a.garage.addLot(a, lot)
+	}
+	if lotIdx(a.lotList, lot) != -1 {
+		return lot.ID(), nil
+	}
+	lots := append(append([]*Lot(nil), a.lotList...), lot)
+	if err := checkLotIDs(lots); err != nil {
+		return "", err
+	}
+	assignLotIDs(lots)
+	lot.SetTicketIssuer(a.issuer)
+	lot.SetClock(a.clock)
+	a.lotList = lots
+	if err := a.saveGarage(); err != nil {
+		a.lotList = a.lotList[:len(a.lotList)-1]
+		return "", err
+	}
+	a.avail.track(lot)
+	lot.Events().Subscribe(a)
+	return lot.ID(), nil
+}
+
+func (a *Attendant) ResizeLot(lotID string, capacity int) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	lot, err := findLot(a.lotList, lotID)
+	if err != nil {
+		return err
+	}
+	previous := lot.Capacity()
+	if err := lot.Resize(capacity); err != nil {
+		return err
+	}
+	if err := a.saveGarage(); err != nil {
+		_ = lot.Resize(previous)
+		return err
+	}
+	return nil
+}
+
+func (a *Attendant) CloseLot(lotID string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	lot, err := findLot(a.lotList, lotID)
+	if err != nil {
+		return err
+	}
+	if err := lot.Close(); err != nil {
+		return err
+	}
+	if err := a.saveGarage(); err != nil {
+		_ = lot.Reopen()
+		return err
+	}
+	return nil
+}
+
+func (a *Attendant) ReopenLot(lotID string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	lot, err := findLot(a.lotList, lotID)
+	if err != nil {
+		return err
+	}
+	if err := lot.Reopen(); err != nil {
+		return err
+	}
+	if err := a.saveGarage(); err != nil {
+		_ = lot.Close()
+		return err
+	}
+	return nil
+}
+
+func findLot(lots []*Lot, lotID string) (*Lot, error) {
+	for _, l := range lots {
+		if l.ID() == lotID {
+			return l, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownLot, lotID)
+}
+
+func (a *Attendant) saveGarage() error {
+	if a.repo == nil {
+		return nil
+	}
+	return a.repo.SaveGarage(a.garageRecord())
+}
+
+func (l *Lot) Resize(capacity int) error {
+	if capacity < 1 {
+		return ErrInvalidCapacity
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if capacity < l.capacity {
+		if capacity < l.usedSlots+l.reservedSlots(l.clock.Now()) {
+			return l.wrapErr(ErrBelowOccupancy)
+		}
+		for _, space := range l.spaces[capacity:] {
+			if !space.IsFree() {
+				return l.wrapErr(ErrBelowOccupancy)
+			}
+		}
+		l.spaces = l.spaces[:capacity]
+	} else {
+		l.spaces = append(l.spaces, NumberedSpaces(capacity, l.spacesPerRow, l.level)[len(l.spaces):]...)
+	}
+	l.capacity = capacity
+	l.publishCapacityChanged()
+	return nil
+}
+
+func (l *Lot) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return l.wrapErr(ErrLotAlreadyClosed)
+	}
+	l.closed = true
+	l.events.Publish(LotClosed{Lot: l})
+	l.publishFullness()
+	return nil
+}
+
+func (l *Lot) Reopen() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.closed {
+		return l.wrapErr(ErrLotNotClosed)
+	}
+	l.closed = false
+	l.events.Publish(LotReopened{Lot: l})
+	l.publishFullness()
+	return nil
+}
+
+func (l *Lot) IsClosed() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.closed
+}
+
+func (l *Lot) isAvailable() bool {
+	return !l.closed && l.isNotFull()
+}
@@ -0,0 +1,198 @@
+package parking_test
+
+import (
+	"testing"
+
+	"github.com/adityatresnobudi/parking-system/entity"
+	"github.com/adityatresnobudi/parking-system/parking"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLotAdministration(t *testing.T) {
+
+	t.Run("should park in a lot added at runtime", func(t *testing.T) {
+		a := parking.NewAttendant([]*parking.Lot{parking.NewLot(1)})
+		_, _ = a.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		added := parking.NewLot(1)
+
+		lotID, err := a.AddLot(added)
+		ticket, parkErr := a.Park(&entity.Car{PlateNumber: "P O LE"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "2", lotID)
+		assert.Nil(t, parkErr)
+		assert.NotNil(t, ticket)
+		assert.Equal(t, 0, added.FreeSpace())
+	})
+
+	t.Run("should grow lot and make it available again", func(t *testing.T) {
+		l := parking.NewLot(1)
+		a := parking.NewAttendant([]*parking.Lot{l})
+		_, _ = a.Park(&entity.Car{PlateNumber: "T 3 ST"})
+
+		err := a.ResizeLot("1", 12)
+		ticket, _ := a.Park(&entity.Car{PlateNumber: "P O LE"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 12, l.Capacity())
+		assert.Equal(t, []*parking.Lot{l}, a.GetAvailLots())
+		assert.Equal(t, "1-A-02", ticket.Space)
+	})
+
+	t.Run("should shrink lot and mark it full when no space is left", func(t *testing.T) {
+		l := parking.NewLot(3)
+		a := parking.NewAttendant([]*parking.Lot{l})
+		_, _ = a.Park(&entity.Car{PlateNumber: "T 3 ST"})
+
+		err := a.ResizeLot("1", 1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, l.FreeSpace())
+		assert.Empty(t, a.GetAvailLots())
+	})
+
+	t.Run("should refuse to shrink lot below occupancy", func(t *testing.T) {
+		l := parking.NewLot(3)
+		a := parking.NewAttendant([]*parking.Lot{l})
+		_, _ = a.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		_, _ = a.Park(&entity.Car{PlateNumber: "P O LE"})
+
+		err := a.ResizeLot("1", 1)
+
+		assert.ErrorIs(t, err, parking.ErrBelowOccupancy)
+		assert.Equal(t, 3, l.Capacity())
+	})
+
+	t.Run("should refuse to drop a space that is still occupied", func(t *testing.T) {
+		l := parking.NewLot(3)
+		a := parking.NewAttendant([]*parking.Lot{l})
+		ticket, _ := a.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		_, _ = a.Park(&entity.Car{PlateNumber: "P O LE"})
+		_, _ = a.UnPark(ticket)
+
+		err := a.ResizeLot("1", 1)
+
+		assert.ErrorIs(t, err, parking.ErrBelowOccupancy)
+	})
+
+	t.Run("should return error for invalid capacity or unknown lot", func(t *testing.T) {
+		a := parking.NewAttendant([]*parking.Lot{parking.NewLot(1)})
+
+		assert.ErrorIs(t, a.ResizeLot("1", 0), parking.ErrInvalidCapacity)
+		assert.ErrorIs(t, a.ResizeLot("2", 1), parking.ErrUnknownLot)
+		assert.ErrorIs(t, a.CloseLot("0"), parking.ErrUnknownLot)
+	})
+
+	t.Run("should stop selecting closed lot but still let cars leave", func(t *testing.T) {
+		l1, l2 := parking.NewLot(2), parking.NewLot(2)
+		a := parking.NewAttendant([]*parking.Lot{l1, l2})
+		ticket, _ := a.Park(&entity.Car{PlateNumber: "T 3 ST"})
+
+		closeErr := a.CloseLot("1")
+		next, _ := a.Park(&entity.Car{PlateNumber: "P O LE"})
+		receipt, unParkErr := a.UnPark(ticket)
+
+		assert.Nil(t, closeErr)
+		assert.Equal(t, 1, l2.FreeSpace())
+		assert.NotNil(t, next)
+		assert.Nil(t, unParkErr)
+		assert.Equal(t, "T 3 ST", receipt.Car.PlateNumber)
+		assert.Equal(t, []*parking.Lot{l2}, a.GetAvailLots())
+	})
+
+	t.Run("should reject parking and reservations directly on a closed lot", func(t *testing.T) {
+		l := parking.NewLot(1)
+		a := parking.NewAttendant([]*parking.Lot{l})
+		_ = a.CloseLot("1")
+
+		ticket, err := l.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		_, attendantErr := a.Park(&entity.Car{PlateNumber: "T 3 ST"})
+
+		assert.Nil(t, ticket)
+		assert.ErrorIs(t, err, parking.ErrLotClosed)
+		assert.ErrorIs(t, attendantErr, parking.ErrUnavailablePosition)
+	})
+
+	t.Run("should select lot again after reopening", func(t *testing.T) {
+		l := parking.NewLot(1)
+		a := parking.NewAttendant([]*parking.Lot{l})
+		_ = a.CloseLot("1")
+
+		err := a.ReopenLot("1")
+		ticket, parkErr := a.Park(&entity.Car{PlateNumber: "T 3 ST"})
+
+		assert.Nil(t, err)
+		assert.Nil(t, parkErr)
+		assert.NotNil(t, ticket)
+		assert.ErrorIs(t, a.ReopenLot("1"), parking.ErrLotNotClosed)
+	})
+
+	t.Run("should persist closed lots and restore them closed", func(t *testing.T) {
+		repo := &memoryRepository{}
+		a, _ := parking.SetupHandler("1,2", repo)
+		_ = a.CloseLot("2")
+		_, _ = a.AddLot(parking.NewLot(3))
+
+		restored, err := parking.RestoreAttendant(repo)
+
+		assert.Nil(t, err)
+		assert.Len(t, restored.Status(), 3)
+		assert.Len(t, restored.GetAvailLots(), 2)
+		assert.Nil(t, restored.ReopenLot("2"))
+		assert.Len(t, restored.GetAvailLots(), 3)
+	})
+
+	t.Run("should roll back resize when repository fails", func(t *testing.T) {
+		repo := &memoryRepository{}
+		a, _ := parking.SetupHandler("1", repo)
+		repo.failing = true
+
+		err := a.ResizeLot("1", 5)
+
+		assert.ErrorIs(t, err, errRepositoryDown)
+		assert.Equal(t, 1, repo.garage.Lots[0].Capacity)
+		assert.Equal(t, 1, a.GetAvailLots()[0].Capacity())
+	})
+}
+
+func TestLotAdministrationByID(t *testing.T) {
+
+	t.Run("should return error when adding lot with an id already in use", func(t *testing.T) {
+		a, _ := parking.SetupHandler("A:1", nil)
+		lot := parking.NewLot(1)
+		lot.SetInfo(parking.LotInfo{ID: "A"})
+
+		id, err := a.AddLot(lot)
+
+		assert.Equal(t, "", id)
+		assert.ErrorIs(t, err, parking.ErrDuplicateLotID)
+		assert.Len(t, a.Status(), 1)
+	})
+
+	t.Run("should name unknown lot id in error", func(t *testing.T) {
+		a, _ := parking.SetupHandler("A:1", nil)
+
+		err := a.CloseLot("Z")
+
+		assert.ErrorIs(t, err, parking.ErrUnknownLot)
+		assert.EqualError(t, err, "unknown parking lot: Z")
+	})
+
+	t.Run("should keep lot ids and metadata after restore", func(t *testing.T) {
+		repo := &memoryRepository{}
+		lot := parking.NewLot(1)
+		lot.SetInfo(parking.LotInfo{ID: "A", Name: "North", Location: "Level 2", Tags: []string{"ev"}})
+		a := parking.NewAttendant([]*parking.Lot{parking.NewLot(1), lot})
+		_ = a.UseRepository(repo)
+		ticket, _ := a.Park(&entity.Car{PlateNumber: "T 3 ST"})
+
+		restored, _ := parking.RestoreAttendant(repo)
+		status, _ := parking.StatusHandler(restored)
+		receipt, err := restored.UnPark(ticket)
+
+		assert.Contains(t, status, "Lot #1: 0 spaces left")
+		assert.Contains(t, status, "Lot #A North @ Level 2 [ev]: 1 spaces left")
+		assert.Nil(t, err)
+		assert.Equal(t, "1", receipt.Ticket.Lot)
+	})
+}
@@ -1,59 +1,294 @@
 package parking
 
-import "github.com/adityatresnobudi/parking-system/entity"
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/adityatresnobudi/parking-system/entity"
+	"github.com/adityatresnobudi/parking-system/plate"
+)
 
 type Attendant struct {
-	lotList       []*Lot
-	availableLots []*Lot
-	parkingStyle  LotSelector
+	mu           *sync.Mutex
+	lotList      []*Lot
+	avail        *availability
+	garage       *Garage
+	scoped       bool
+	parkingStyle LotSelector
+	rules        []SelectionRule
+	plates       *plate.Validator
+	issuer       entity.TicketIssuer
+	clock        Clock
+	tariff       Tariff
+	repo         Repository
+	audit        AuditTrail
+	lostPenalty  int
+	events       *SyncBus
 }
 
+// LotSelector picks one of the candidate lots and returns its index in lots,
+// or -1 when none of them is acceptable. Selectors only see snapshots, so
+// picking a lot has no side effects.
 type LotSelector interface {
-	SelectLot(lots []*Lot) *Lot
+	SelectLot(lots []LotView) int
 }
 
 func NewAttendant(lots []*Lot) *Attendant {
-	tLot := make([]*Lot, len(lots))
-	copy(tLot, lots)
+	assignLotIDs(lots)
+	a := newAttendant(lots, newAvailability(lots), &sync.Mutex{})
+	a.SetTicketIssuer(entity.DefaultTicketIssuer)
+	a.SetClock(SystemClock{})
+	return a
+}
+
+func newAttendant(lots []*Lot, avail *availability, mu *sync.Mutex) *Attendant {
 	a := &Attendant{
-		lotList:       lots,
-		availableLots: tLot,
-		parkingStyle:  &FirstAvailable{},
+		mu:           mu,
+		lotList:      lots,
+		avail:        avail,
+		parkingStyle: &FirstAvailable{},
+		plates:       plate.NewValidator(),
+		issuer:       entity.DefaultTicketIssuer,
+		clock:        SystemClock{},
+		tariff:       DefaultTariff,
+		audit:        NewMemoryAuditTrail(),
+		lostPenalty:  DefaultLostTicketPenalty,
+		events:       NewSyncBus(),
 	}
 	a.SubsribeAllLot()
 	return a
 }
 
 func (a *Attendant) Park(car *entity.Car) (*entity.Ticket, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	ticket, err := a.park(car)
+	if err != nil {
+		a.recordParkFailed(car, err)
+	}
+	return ticket, err
+}
+
+func (a *Attendant) park(car *entity.Car) (*entity.Ticket, error) {
 	if a.isCarParked(car) {
-		return nil, ErrParkedCarTwice
+		return a.reject(car, ErrParkedCarTwice)
+	}
+	a.expireReservations()
+	reserved, r := a.findReservation(car.PlateNumber)
+	lot, ticket, err := a.placeCar(car, reserved, r)
+	if err != nil {
+		if reserved != nil {
+			_, _ = reserved.reserve(r)
+		}
+		return nil, err
+	}
+	undo := func() {
+		_, _ = lot.UnPark(ticket)
+		if reserved != nil {
+			_, _ = reserved.reserve(r)
+		}
+	}
+	if err := a.persistPark(lot, ticket, car, reserved != nil); err != nil {
+		undo()
+		return nil, err
+	}
+	if err := a.recordPark(car, ticket); err != nil {
+		undo()
+		if reserved != nil {
+			_ = a.saveGarage()
+		} else if a.repo != nil {
+			_ = a.repo.DeleteTicket(ticket.ID)
+		}
+		return nil, err
+	}
+	return ticket, nil
+}
+
+// placeCar parks car in its reserved lot, or picks a lot the usual way when
+// there is no reservation or the reserved lot refuses the car, e.g. because it
+// arrived as a different vehicle type. The reservation is used up either way;
+// park puts it back when the car cannot be parked.
+func (a *Attendant) placeCar(car *entity.Car, reserved *Lot, r Reservation) (*Lot, *entity.Ticket, error) {
+	if reserved != nil {
+		if ticket, err := reserved.ParkReserved(car, r.ID); err == nil {
+			return reserved, ticket, nil
+		}
+		reserved.cancelReservation(r.ID)
+	}
+	if len(a.lotList) > 0 && !a.isVehicleAccepted(car) {
+		_, err := a.reject(car, ErrVehicleNotAccepted)
+		return nil, nil, err
+	}
+	candidates := a.fittingLots(car)
+	if len(candidates) == 0 {
+		_, err := a.reject(car, ErrUnavailablePosition)
+		return nil, nil, err
+	}
+	selectedLot, err := a.selectLot(a.styleFor(car), candidates)
+	if errors.Is(err, ErrUnavailablePosition) {
+		_, err = a.reject(car, err)
+		return nil, nil, err
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	ticket, err := selectedLot.Park(car)
+	if err != nil {
+		return nil, nil, err
+	}
+	return selectedLot, ticket, nil
+}
+
+func (a *Attendant) UnPark(ticket *entity.Ticket) (*Receipt, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	i := a.findTicket(ticket)
+	if i == -1 {
+		a.recordUnParkFailed(ticket.ID, ErrUnrecognizedParkingTicket)
+		return nil, ErrUnrecognizedParkingTicket
+	}
+	return a.checkout(a.lotList[i], ticket.ID, 0, AuditUnPark)
+}
+
+func (a *Attendant) FindLostTicket(plateNumber string) (*Receipt, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	lot, ticketID := a.findPlate(plateNumber)
+	if lot == nil {
+		return nil, ErrCarNotFound
+	}
+	issued, _ := lot.ticket(ticketID)
+	return a.receipt(issued, lot.parkedCar(ticketID), a.lostPenalty), nil
+}
+
+func (a *Attendant) ReleaseLostTicket(plateNumber string) (*Receipt, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	lot, ticketID := a.findPlate(plateNumber)
+	if lot == nil {
+		return nil, ErrCarNotFound
+	}
+	return a.checkout(lot, ticketID, a.lostPenalty, AuditLostTicket)
+}
+
+// checkout deletes the saved ticket, records the audit event and only then
+// releases the space, so the audit trail never reports an exit that did not
+// happen. A failed audit write puts the saved ticket back.
+func (a *Attendant) checkout(lot *Lot, ticketID string, penalty int, action string) (*Receipt, error) {
+	issued, _ := lot.ticket(ticketID)
+	car := lot.parkedCar(ticketID)
+	receipt := a.receipt(issued, car, penalty)
+	if a.repo != nil {
+		if err := a.repo.DeleteTicket(ticketID); err != nil {
+			a.recordUnParkFailed(ticketID, err)
+			return nil, err
+		}
+	}
+	if err := a.recordUnPark(action, receipt); err != nil {
+		if a.repo != nil {
+			_ = a.repo.SaveTicket(lot.ID(), TicketRecord{Ticket: issued, Car: *car})
+		}
+		return nil, err
 	}
-	if lot := a.findAvailableLot(a.lotList); lot != nil {
-		selectedLot := a.parkingStyle.SelectLot(a.availableLots)
-		return selectedLot.Park(car)
+	if _, err := lot.UnPark(&issued); err != nil {
+		return nil, err
 	}
-	return nil, ErrUnavailablePosition
+	return receipt, nil
 }
 
-func (a *Attendant) UnPark(ticket *entity.Ticket) (*entity.Car, error) {
-	if i := a.findTicket(ticket); i != -1 {
-		return a.lotList[i].UnPark(ticket)
+func (a *Attendant) receipt(issued entity.Ticket, car *entity.Car, penalty int) *Receipt {
+	exit := a.clock.Now()
+	return &Receipt{
+		Ticket:    issued,
+		Car:       car,
+		EntryTime: issued.EntryTime,
+		ExitTime:  exit,
+		Duration:  exit.Sub(issued.EntryTime),
+		Penalty:   penalty,
+		Amount:    a.tariff.Calculate(issued.EntryTime, exit) + penalty,
 	}
-	return nil, ErrUnrecognizedParkingTicket
 }
 
-func (a *Attendant) findAvailableLot(lotList []*Lot) *Lot {
-	for _, val := range a.lotList {
-		if val.IsNotFull() {
-			return val
+func (a *Attendant) findPlate(plateNumber string) (*Lot, string) {
+	for _, lot := range a.lotList {
+		if ticketID, ok := lot.findPlate(plateNumber); ok {
+			return lot, ticketID
 		}
 	}
-	return nil
+	return nil, ""
+}
+
+// persistPark saves the whole garage when a reservation was used up, so the
+// saved reservations match the lots, and only the new ticket otherwise.
+func (a *Attendant) persistPark(lot *Lot, ticket *entity.Ticket, car *entity.Car, reservation bool) error {
+	if reservation {
+		return a.saveGarage()
+	}
+	if a.repo == nil {
+		return nil
+	}
+	return a.repo.SaveTicket(lot.ID(), TicketRecord{Ticket: *ticket, Car: *car})
+}
+
+func (a *Attendant) CanPark(car *entity.Car) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.fittingLots(car)) > 0
+}
+
+func (a *Attendant) Accepts(vt entity.VehicleType) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.isVehicleAccepted(&entity.Car{Type: vt})
+}
+
+func (a *Attendant) HasTicket(ticket *entity.Ticket) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.findTicket(ticket) != -1
+}
+
+func (a *Attendant) IsCarParked(car *entity.Car) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.isCarParked(car)
+}
+
+func (a *Attendant) fittingLots(car *entity.Car) []*Lot {
+	output := make([]*Lot, 0)
+	for _, l := range a.availLots() {
+		if l.CanFit(car) {
+			output = append(output, l)
+		}
+	}
+	return output
+}
+
+func (a *Attendant) selectLot(style LotSelector, candidates []*Lot) (*Lot, error) {
+	views := LotViews(candidates)
+	choice := style.SelectLot(views)
+	if choice == -1 {
+		return nil, ErrUnavailablePosition
+	}
+	if choice < 0 || choice >= len(views) {
+		return nil, fmt.Errorf("%w: %d of %d", ErrInvalidLotSelection, choice, len(views))
+	}
+	return candidates[choice], nil
+}
+
+func (a *Attendant) isVehicleAccepted(car *entity.Car) bool {
+	for _, l := range a.lotList {
+		if l.Accepts(car.VehicleType()) {
+			return true
+		}
+	}
+	return false
 }
 
 func (a *Attendant) findTicket(ticket *entity.Ticket) int {
 	for idx, val := range a.lotList {
-		if _, ok := val.parkedCars[ticket.ID]; ok {
+		if _, ok := val.ticket(ticket.ID); ok {
 			return idx
 		}
 	}
@@ -61,7 +296,11 @@ func (a *Attendant) findTicket(ticket *entity.Ticket) int {
 }
 
 func (a *Attendant) isCarParked(car *entity.Car) bool {
-	for _, lot := range a.lotList {
+	lots := a.lotList
+	if a.garage != nil {
+		lots = a.garage.lots
+	}
+	for _, lot := range lots {
 		if lot.IsCarParked(car) {
 			return true
 		}
@@ -71,39 +310,141 @@ func (a *Attendant) isCarParked(car *entity.Car) bool {
 
 func (a *Attendant) SubsribeAllLot() {
 	for _, l := range a.lotList {
-		l.Subscribe(a)
+		l.Events().Subscribe(a)
+	}
+}
+
+func (a *Attendant) HandleEvent(e Event) {
+	a.events.Publish(e)
+}
+
+func (a *Attendant) Events() EventBus {
+	return a.events
+}
+
+func (a *Attendant) reject(car *entity.Car, err error) (*entity.Ticket, error) {
+	a.events.Publish(ParkRejected{Car: car, Err: err})
+	return nil, err
+}
+
+func (a *Attendant) SetTicketIssuer(issuer entity.TicketIssuer) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.issuer = issuer
+	for _, l := range a.lotList {
+		l.SetTicketIssuer(issuer)
+		l.observeTickets()
+	}
+}
+
+func (a *Attendant) ObserveTicket(id string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if o, ok := a.issuer.(interface{ Observe(string) }); ok {
+		o.Observe(id)
+	}
+	for _, l := range a.lotList {
+		l.observeTicket(id)
+	}
+}
+
+func (a *Attendant) SetPlateFormats(formats ...plate.Format) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.plates = plate.NewValidator(formats...)
+}
+
+// ValidatePlate normalizes a plate and checks it against the attendant's
+// plate formats.
+func (a *Attendant) ValidatePlate(plateNumber string) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.plates.Validate(plateNumber)
+}
+
+func (a *Attendant) SetClock(clock Clock) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.clock = clock
+	for _, l := range a.lotList {
+		l.SetClock(clock)
 	}
 }
 
-func (a *Attendant) NotifyLotIsFull(lot *Lot) {
-	fullLotIdx := a.lotIdx(a.availableLots, lot)
-	a.availableLots = append(a.availableLots[:fullLotIdx], a.availableLots[fullLotIdx+1:]...)
+func (a *Attendant) SetLostTicketPenalty(penalty int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.lostPenalty = penalty
 }
 
-func (a *Attendant) NotifyLotIsNotFull(lot *Lot) {
-	a.availableLots = append(a.availableLots, lot)
+func (a *Attendant) SetAuditTrail(audit AuditTrail) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.audit = audit
 }
 
-func (a *Attendant) lotIdx(lots []*Lot, lot *Lot) int {
-	output := 0
-	for idx, l := range lots {
-		if lot == l {
-			output = idx
-			break
+// ResumeAuditTrail switches to audit and observes every ticket ID it has
+// recorded, so tickets of cars that already left are not issued again after a
+// restart.
+func (a *Attendant) ResumeAuditTrail(audit AuditTrail) error {
+	events, err := audit.Events()
+	if err != nil {
+		return err
+	}
+	a.SetAuditTrail(audit)
+	for _, e := range events {
+		if e.TicketID != "" {
+			a.ObserveTicket(e.TicketID)
 		}
 	}
-	return output
+	return nil
+}
+
+func (a *Attendant) History(query AuditQuery) ([]AuditEvent, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return QueryAudit(a.audit, query)
+}
+
+func (a *Attendant) SetTariff(tariff Tariff) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.tariff = tariff
+}
+
+func (a *Attendant) PrefixTicketsPerLot() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, l := range a.lotList {
+		l.SetTicketIssuer(entity.NewPrefixedIssuer(fmt.Sprintf("L%s-", l.ID()), a.issuer))
+	}
 }
 
 func (a *Attendant) ChangeStyle(style LotSelector) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 	a.parkingStyle = style
 }
 
+func (a *Attendant) Style() LotSelector {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.parkingStyle
+}
+
 func (a *Attendant) GetAvailLots() []*Lot {
-	return a.availableLots
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.availLots()
+}
+
+func (a *Attendant) availLots() []*Lot {
+	return a.avail.within(a.lotList)
 }
 
 func (a *Attendant) Status() []LotStatus {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 	output := make([]LotStatus, 0)
 	for _, lot := range a.lotList {
 		output = append(output, lot.Status())
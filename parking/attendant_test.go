@@ -1,7 +1,9 @@
 package parking_test
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/adityatresnobudi/parking-system/parking"
 	"github.com/adityatresnobudi/parking-system/entity"
@@ -9,6 +11,14 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+type fakeClock struct {
+	now time.Time
+}
+
+func (fc *fakeClock) Now() time.Time {
+	return fc.now
+}
+
 func TestAttendantPark(t *testing.T) {
 
 	t.Run("should return ticket if park succeeded", func(t *testing.T) {
@@ -140,14 +150,14 @@ func TestAttendantPark(t *testing.T) {
 		l1 := parking.NewLot(1)
 		l2 := parking.NewLot(2)
 		car := &entity.Car{PlateNumber: "T 3 ST"}
-		expected := l1
 		a := parking.NewAttendant([]*parking.Lot{l1, l2})
 		a.ChangeStyle(mockLotSelector)
 
-		mockLotSelector.On("SelectLot", a.GetAvailLots()).Return(expected)
+		mockLotSelector.On("SelectLot", parking.LotViews(a.GetAvailLots())).Return(1)
 		ticket, _ := a.Park(car)
 
 		assert.NotNil(t, ticket)
+		assert.Equal(t, l2.ID(), ticket.Lot)
 	})
 
 	t.Run("should park car in highest capacity lot when parking style is HighestCapacity", func(t *testing.T) {
@@ -186,6 +196,145 @@ func TestAttendantPark(t *testing.T) {
 	})
 }
 
+func TestAttendantVehicleTypes(t *testing.T) {
+
+	t.Run("should park vehicle only in lots that can fit it", func(t *testing.T) {
+		small := parking.NewLot(1)
+		large := parking.NewLot(3)
+		a := parking.NewAttendant([]*parking.Lot{small, large})
+		van := &entity.Car{PlateNumber: "V 4 N", Type: entity.VehicleVan}
+
+		ticket, err := a.Park(van)
+		returnedVan, _ := large.UnPark(ticket)
+
+		assert.Nil(t, err)
+		assert.Same(t, van, returnedVan)
+	})
+
+	t.Run("should park vehicle only in lots that accept its type", func(t *testing.T) {
+		carsOnly := parking.NewLotWithVehicles(5, map[entity.VehicleType]int{entity.VehicleCar: 1})
+		bikes := parking.NewLotWithVehicles(5, map[entity.VehicleType]int{entity.VehicleMotorcycle: 1})
+		a := parking.NewAttendant([]*parking.Lot{carsOnly, bikes})
+		bike := &entity.Car{PlateNumber: "M 0 TR", Type: entity.VehicleMotorcycle}
+
+		ticket, err := a.Park(bike)
+		returnedBike, _ := bikes.UnPark(ticket)
+
+		assert.Nil(t, err)
+		assert.Same(t, bike, returnedBike)
+	})
+
+	t.Run("should return error when no lot accepts vehicle type", func(t *testing.T) {
+		carsOnly := parking.NewLotWithVehicles(5, map[entity.VehicleType]int{entity.VehicleCar: 1})
+		a := parking.NewAttendant([]*parking.Lot{carsOnly})
+		bus := &entity.Car{PlateNumber: "B 7 US", Type: entity.VehicleBus}
+
+		ticket, err := a.Park(bus)
+
+		assert.Nil(t, ticket)
+		assert.ErrorIs(t, err, parking.ErrVehicleNotAccepted)
+	})
+
+	t.Run("should return error when no lot has enough room for vehicle", func(t *testing.T) {
+		a := parking.NewAttendant([]*parking.Lot{parking.NewLot(2), parking.NewLot(2)})
+		bus := &entity.Car{PlateNumber: "B 7 US", Type: entity.VehicleBus}
+
+		ticket, err := a.Park(bus)
+
+		assert.Nil(t, ticket)
+		assert.ErrorIs(t, err, parking.ErrUnavailablePosition)
+	})
+}
+
+func TestAttendantTicketIssuing(t *testing.T) {
+
+	t.Run("should issue unique tickets when parking thousands of cars across lots", func(t *testing.T) {
+		lots := []*parking.Lot{parking.NewLot(2000), parking.NewLot(2000), parking.NewLot(1000)}
+		a := parking.NewAttendant(lots)
+		seen := make(map[string]bool)
+
+		for i := 0; i < 5000; i++ {
+			ticket, err := a.Park(&entity.Car{PlateNumber: fmt.Sprintf("B %d X", i)})
+			assert.Nil(t, err)
+			assert.False(t, seen[ticket.ID])
+			seen[ticket.ID] = true
+		}
+		assert.Len(t, seen, 5000)
+	})
+
+	t.Run("should use attendant ticket issuer for every lot", func(t *testing.T) {
+		l1 := parking.NewLot(1)
+		l2 := parking.NewLot(1)
+		a := parking.NewAttendant([]*parking.Lot{l1, l2})
+		a.SetTicketIssuer(entity.NewSequentialIssuer(1))
+
+		ticket1, _ := a.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		ticket2, _ := a.Park(&entity.Car{PlateNumber: "P O LE"})
+
+		assert.Equal(t, "1", ticket1.ID)
+		assert.Equal(t, "2", ticket2.ID)
+	})
+
+	t.Run("should prefix ticket with lot number when prefixing tickets per lot", func(t *testing.T) {
+		l1 := parking.NewLot(1)
+		l2 := parking.NewLot(1)
+		a := parking.NewAttendant([]*parking.Lot{l1, l2})
+		a.SetTicketIssuer(entity.NewSequentialIssuer(1))
+		a.PrefixTicketsPerLot()
+
+		ticket1, _ := a.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		ticket2, _ := a.Park(&entity.Car{PlateNumber: "P O LE"})
+
+		assert.Equal(t, "L1-1", ticket1.ID)
+		assert.Equal(t, "L2-2", ticket2.ID)
+	})
+
+	t.Run("should prefix ticket with lot id when prefixing tickets per lot", func(t *testing.T) {
+		l1 := parking.NewLot(1)
+		l2 := parking.NewLot(1)
+		l1.SetInfo(parking.LotInfo{ID: "B"})
+		l2.SetInfo(parking.LotInfo{ID: "A"})
+		a := parking.NewAttendant([]*parking.Lot{l1, l2})
+		a.SetTicketIssuer(entity.NewSequentialIssuer(1))
+		a.PrefixTicketsPerLot()
+
+		ticket1, _ := a.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		ticket2, _ := a.Park(&entity.Car{PlateNumber: "P O LE"})
+
+		assert.Equal(t, "LB-1", ticket1.ID)
+		assert.Equal(t, "LA-2", ticket2.ID)
+	})
+
+	t.Run("should not collide when every lot uses its own ULID issuer", func(t *testing.T) {
+		l1 := parking.NewLot(1000)
+		l2 := parking.NewLot(1000)
+		a := parking.NewAttendant([]*parking.Lot{l1, l2})
+		l1.SetTicketIssuer(entity.NewULIDIssuer())
+		l2.SetTicketIssuer(entity.NewULIDIssuer())
+		a.ChangeStyle(&parking.HighestFreeSpace{})
+		seen := make(map[string]bool)
+
+		for i := 0; i < 2000; i++ {
+			ticket, err := a.Park(&entity.Car{PlateNumber: fmt.Sprintf("B %d X", i)})
+			assert.Nil(t, err)
+			seen[ticket.ID] = true
+		}
+		assert.Len(t, seen, 2000)
+	})
+
+	t.Run("should not reissue ids of parked cars after issuer is replaced", func(t *testing.T) {
+		a := parking.NewAttendant([]*parking.Lot{parking.NewLot(2)})
+		a.SetTicketIssuer(entity.NewSequentialIssuer(1000))
+		_, _ = a.Park(&entity.Car{PlateNumber: "T 3 ST"})
+
+		a.SetTicketIssuer(entity.NewSequentialIssuer(1000))
+		ticket, err := a.Park(&entity.Car{PlateNumber: "P O LE"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "1001", ticket.ID)
+	})
+}
+
 func TestAttendantUnPark(t *testing.T) {
 
 	t.Run("should return car when unpark if ticket exist when unpark", func(t *testing.T) {
@@ -195,12 +344,28 @@ func TestAttendantUnPark(t *testing.T) {
 		ticket, _ := a.Park(car)
 		expected := car
 
-		returnedCar, err := a.UnPark(ticket)
+		receipt, err := a.UnPark(ticket)
 
-		assert.Same(t, expected, returnedCar)
+		assert.Same(t, expected, receipt.Car)
 		assert.Nil(t, err)
 	})
 
+	t.Run("should return receipt with duration and amount due when unpark", func(t *testing.T) {
+		p := parking.NewLot(2)
+		a := parking.NewAttendant([]*parking.Lot{p})
+		clock := &fakeClock{now: time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC)}
+		a.SetClock(clock)
+		a.SetTariff(&parking.HourlyTariff{FirstHour: 5000, HourlyRate: 3000})
+		ticket, _ := a.Park(&entity.Car{PlateNumber: "T 3 ST"})
+
+		clock.now = clock.now.Add(2*time.Hour + 30*time.Minute)
+		receipt, err := a.UnPark(ticket)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2*time.Hour+30*time.Minute, receipt.Duration)
+		assert.Equal(t, 11000, receipt.Amount)
+	})
+
 	t.Run("should return error when unpark if ticket does not exist", func(t *testing.T) {
 		p := parking.NewLot(2)
 		a := parking.NewAttendant([]*parking.Lot{p})
@@ -236,3 +401,74 @@ func TestAttendantUnPark(t *testing.T) {
 		assert.ErrorIs(t, err, parking.ErrUnrecognizedParkingTicket)
 	})
 }
+
+func TestAttendantLostTicket(t *testing.T) {
+
+	t.Run("should return error when releasing lost ticket for car not inside", func(t *testing.T) {
+		a := parking.NewAttendant([]*parking.Lot{parking.NewLot(1)})
+
+		receipt, err := a.ReleaseLostTicket("T 3 ST")
+
+		assert.Nil(t, receipt)
+		assert.ErrorIs(t, err, parking.ErrCarNotFound)
+	})
+
+	t.Run("should release car by plate number and charge lost ticket penalty", func(t *testing.T) {
+		l1 := parking.NewLot(1)
+		l2 := parking.NewLot(1)
+		a := parking.NewAttendant([]*parking.Lot{l1, l2})
+		clock := &fakeClock{now: time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC)}
+		a.SetClock(clock)
+		a.SetTariff(&parking.HourlyTariff{FirstHour: 5000, HourlyRate: 3000})
+		a.SetLostTicketPenalty(20000)
+		car := &entity.Car{PlateNumber: "T 3 ST"}
+		_, _ = a.Park(&entity.Car{PlateNumber: "P O LE"})
+		_, _ = a.Park(car)
+
+		clock.now = clock.now.Add(30 * time.Minute)
+		receipt, err := a.ReleaseLostTicket("T 3 ST")
+
+		assert.Nil(t, err)
+		assert.Same(t, car, receipt.Car)
+		assert.Equal(t, 20000, receipt.Penalty)
+		assert.Equal(t, 25000, receipt.Amount)
+		assert.Equal(t, 1, l2.FreeSpace())
+	})
+
+	t.Run("should quote lost ticket penalty without releasing car", func(t *testing.T) {
+		l := parking.NewLot(1)
+		a := parking.NewAttendant([]*parking.Lot{l})
+		_, _ = a.Park(&entity.Car{PlateNumber: "T 3 ST"})
+
+		receipt, err := a.FindLostTicket("T 3 ST")
+
+		assert.Nil(t, err)
+		assert.Equal(t, parking.DefaultLostTicketPenalty, receipt.Penalty)
+		assert.Equal(t, 0, l.FreeSpace())
+	})
+
+	t.Run("should record lost ticket release in audit trail", func(t *testing.T) {
+		a := parking.NewAttendant([]*parking.Lot{parking.NewLot(1)})
+		ticket, _ := a.Park(&entity.Car{PlateNumber: "T 3 ST"})
+
+		receipt, _ := a.ReleaseLostTicket("T 3 ST")
+		events, _ := a.History(parking.AuditQuery{Action: parking.AuditLostTicket})
+
+		assert.Len(t, events, 1)
+		assert.Equal(t, parking.AuditLostTicket, events[0].Action)
+		assert.Equal(t, "T 3 ST", events[0].PlateNumber)
+		assert.Equal(t, ticket.ID, events[0].TicketID)
+		assert.Equal(t, receipt.Amount, events[0].Amount)
+	})
+
+	t.Run("should not accept original ticket after lost ticket release", func(t *testing.T) {
+		a := parking.NewAttendant([]*parking.Lot{parking.NewLot(1)})
+		ticket, _ := a.Park(&entity.Car{PlateNumber: "T 3 ST"})
+
+		_, _ = a.ReleaseLostTicket("T 3 ST")
+		receipt, err := a.UnPark(ticket)
+
+		assert.Nil(t, receipt)
+		assert.ErrorIs(t, err, parking.ErrUnrecognizedParkingTicket)
+	})
+}
@@ -0,0 +1,136 @@
+package parking
+
+import (
+	"sync"
+	"time"
+
+	"github.com/adityatresnobudi/parking-system/entity"
+	"github.com/adityatresnobudi/parking-system/plate"
+)
+
+const (
+	AuditPark         = "park"
+	AuditParkFailed   = "park_failed"
+	AuditUnPark       = "unpark"
+	AuditUnParkFailed = "unpark_failed"
+	AuditLostTicket   = "lost_ticket"
+)
+
+type AuditEvent struct {
+	Time        time.Time `json:"time"`
+	Action      string    `json:"action"`
+	PlateNumber string    `json:"plate_number,omitempty"`
+	TicketID    string    `json:"ticket_id,omitempty"`
+	Space       string    `json:"space,omitempty"`
+	Amount      int       `json:"amount,omitempty"`
+	Error       string    `json:"error,omitempty"`
+}
+
+type AuditTrail interface {
+	Record(event AuditEvent) error
+	Events() ([]AuditEvent, error)
+}
+
+type AuditQuery struct {
+	Action      string
+	PlateNumber string
+	TicketID    string
+	From        time.Time
+	To          time.Time
+}
+
+type MemoryAuditTrail struct {
+	mu     sync.Mutex
+	events []AuditEvent
+}
+
+func NewMemoryAuditTrail() *MemoryAuditTrail {
+	return &MemoryAuditTrail{events: make([]AuditEvent, 0)}
+}
+
+func (m *MemoryAuditTrail) Record(event AuditEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, event)
+	return nil
+}
+
+func (m *MemoryAuditTrail) Events() ([]AuditEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	output := make([]AuditEvent, len(m.events))
+	copy(output, m.events)
+	return output, nil
+}
+
+func (q AuditQuery) Matches(e AuditEvent) bool {
+	if q.Action != "" && q.Action != e.Action {
+		return false
+	}
+	if q.PlateNumber != "" && !plate.Equal(q.PlateNumber, e.PlateNumber) {
+		return false
+	}
+	if q.TicketID != "" && q.TicketID != e.TicketID {
+		return false
+	}
+	if !q.From.IsZero() && e.Time.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !e.Time.Before(q.To) {
+		return false
+	}
+	return true
+}
+
+func QueryAudit(trail AuditTrail, query AuditQuery) ([]AuditEvent, error) {
+	events, err := trail.Events()
+	if err != nil {
+		return nil, err
+	}
+	output := make([]AuditEvent, 0)
+	for _, e := range events {
+		if query.Matches(e) {
+			output = append(output, e)
+		}
+	}
+	return output, nil
+}
+
+func (a *Attendant) recordPark(car *entity.Car, ticket *entity.Ticket) error {
+	return a.audit.Record(AuditEvent{
+		Time:        ticket.EntryTime,
+		Action:      AuditPark,
+		PlateNumber: car.PlateNumber,
+		TicketID:    ticket.ID,
+		Space:       ticket.Space,
+	})
+}
+
+func (a *Attendant) recordParkFailed(car *entity.Car, err error) {
+	_ = a.audit.Record(AuditEvent{
+		Time:        a.clock.Now(),
+		Action:      AuditParkFailed,
+		PlateNumber: car.PlateNumber,
+		Error:       err.Error(),
+	})
+}
+
+func (a *Attendant) recordUnPark(action string, receipt *Receipt) error {
+	return a.audit.Record(AuditEvent{
+		Time:        receipt.ExitTime,
+		Action:      action,
+		PlateNumber: receipt.Car.PlateNumber,
+		TicketID:    receipt.Ticket.ID,
+		Space:       receipt.Ticket.Space,
+		Amount:      receipt.Amount,
+	})
+}
+
+func (a *Attendant) recordUnParkFailed(ticketID string, err error) {
+	_ = a.audit.Record(AuditEvent{
+		Time:     a.clock.Now(),
+		Action:   AuditUnParkFailed,
+		TicketID: ticketID,
+		Error:    err.Error(),
+	})
+}
@@ -0,0 +1,127 @@
+package parking_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adityatresnobudi/parking-system/entity"
+	"github.com/adityatresnobudi/parking-system/parking"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingAuditTrail struct {
+	*parking.MemoryAuditTrail
+	err error
+}
+
+func (f *failingAuditTrail) Record(event parking.AuditEvent) error {
+	if f.err != nil {
+		return f.err
+	}
+	return f.MemoryAuditTrail.Record(event)
+}
+
+func TestAuditTrail(t *testing.T) {
+	nine := time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC)
+
+	newAttendant := func() (*parking.Attendant, *fakeClock) {
+		a := parking.NewAttendant([]*parking.Lot{parking.NewLot(1)})
+		clock := &fakeClock{now: nine}
+		a.SetClock(clock)
+		return a, clock
+	}
+
+	t.Run("should record park and unpark transactions", func(t *testing.T) {
+		a, clock := newAttendant()
+		ticket, _ := a.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		clock.now = nine.Add(2 * time.Hour)
+		receipt, _ := a.UnPark(ticket)
+
+		events, err := a.History(parking.AuditQuery{})
+
+		assert.Nil(t, err)
+		assert.Len(t, events, 2)
+		assert.Equal(t, parking.AuditEvent{Time: nine, Action: parking.AuditPark, PlateNumber: "T 3 ST", TicketID: ticket.ID, Space: "1-A-01"}, events[0])
+		assert.Equal(t, parking.AuditEvent{Time: clock.now, Action: parking.AuditUnPark, PlateNumber: "T 3 ST", TicketID: ticket.ID, Space: "1-A-01", Amount: receipt.Amount}, events[1])
+	})
+
+	t.Run("should record failed park and unpark attempts", func(t *testing.T) {
+		a, _ := newAttendant()
+		_, _ = a.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		_, _ = a.Park(&entity.Car{PlateNumber: "P O LE"})
+		_, _ = a.UnPark(&entity.Ticket{ID: "ERR!"})
+
+		parkFailed, _ := a.History(parking.AuditQuery{Action: parking.AuditParkFailed})
+		unParkFailed, _ := a.History(parking.AuditQuery{Action: parking.AuditUnParkFailed})
+
+		assert.Equal(t, []parking.AuditEvent{{Time: nine, Action: parking.AuditParkFailed, PlateNumber: "P O LE", Error: parking.ErrUnavailablePosition.Error()}}, parkFailed)
+		assert.Equal(t, []parking.AuditEvent{{Time: nine, Action: parking.AuditUnParkFailed, TicketID: "ERR!", Error: parking.ErrUnrecognizedParkingTicket.Error()}}, unParkFailed)
+	})
+
+	t.Run("should query history by plate number and ticket id", func(t *testing.T) {
+		a := parking.NewAttendant([]*parking.Lot{parking.NewLot(2)})
+		ticket, _ := a.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		_, _ = a.Park(&entity.Car{PlateNumber: "P O LE"})
+		_, _ = a.UnPark(ticket)
+
+		byPlate, _ := a.History(parking.AuditQuery{PlateNumber: "T 3 ST"})
+		byTicket, _ := a.History(parking.AuditQuery{TicketID: ticket.ID})
+
+		assert.Len(t, byPlate, 2)
+		assert.Equal(t, byPlate, byTicket)
+	})
+
+	t.Run("should query history by half-open time range", func(t *testing.T) {
+		a, clock := newAttendant()
+		ticket, _ := a.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		clock.now = nine.Add(time.Hour)
+		_, _ = a.UnPark(ticket)
+
+		events, _ := a.History(parking.AuditQuery{From: nine, To: nine.Add(time.Hour)})
+
+		assert.Len(t, events, 1)
+		assert.Equal(t, parking.AuditPark, events[0].Action)
+	})
+	t.Run("should keep the car parked when the checkout cannot be recorded", func(t *testing.T) {
+		a, _ := newAttendant()
+		trail := &failingAuditTrail{MemoryAuditTrail: parking.NewMemoryAuditTrail()}
+		a.SetAuditTrail(trail)
+		car := &entity.Car{PlateNumber: "T 3 ST"}
+		ticket, _ := a.Park(car)
+		trail.err = errors.New("disk full")
+
+		unParked, unParkErr := a.UnPark(ticket)
+		released, releaseErr := a.ReleaseLostTicket("T 3 ST")
+
+		assert.Nil(t, unParked)
+		assert.EqualError(t, unParkErr, "disk full")
+		assert.Nil(t, released)
+		assert.EqualError(t, releaseErr, "disk full")
+		assert.True(t, a.IsCarParked(car))
+	})
+	t.Run("should not park the car when the park cannot be recorded", func(t *testing.T) {
+		a, _ := newAttendant()
+		a.SetAuditTrail(&failingAuditTrail{MemoryAuditTrail: parking.NewMemoryAuditTrail(), err: errors.New("disk full")})
+		car := &entity.Car{PlateNumber: "T 3 ST"}
+
+		ticket, err := a.Park(car)
+
+		assert.Nil(t, ticket)
+		assert.EqualError(t, err, "disk full")
+		assert.False(t, a.IsCarParked(car))
+		assert.Len(t, a.GetAvailLots(), 1)
+	})
+	t.Run("should not reissue ticket ids recorded in a resumed audit trail", func(t *testing.T) {
+		a, _ := newAttendant()
+		a.SetTicketIssuer(entity.NewSequentialIssuer(1000))
+		trail := parking.NewMemoryAuditTrail()
+		_ = trail.Record(parking.AuditEvent{Time: nine, Action: parking.AuditUnPark, PlateNumber: "B 1 A", TicketID: "1000"})
+
+		err := a.ResumeAuditTrail(trail)
+		ticket, _ := a.Park(&entity.Car{PlateNumber: "B 2 A"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "1001", ticket.ID)
+	})
+}
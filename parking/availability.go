@@ -0,0 +1,74 @@
+package parking
+
+import "sync"
+
+type availability struct {
+	mu   sync.Mutex
+	lots []*Lot
+}
+
+func newAvailability(lots []*Lot) *availability {
+	av := &availability{lots: make([]*Lot, 0, len(lots))}
+	for _, l := range lots {
+		av.track(l)
+	}
+	return av
+}
+
+func (av *availability) track(lot *Lot) {
+	if lot.IsNotFull() && !lot.IsClosed() {
+		av.markAvailable(lot)
+	}
+	lot.Events().Subscribe(av)
+}
+
+func (av *availability) HandleEvent(e Event) {
+	switch e.(type) {
+	case LotFull:
+		av.markFull(e.Source())
+	case LotAvailable:
+		av.markAvailable(e.Source())
+	}
+}
+
+func (av *availability) markFull(lot *Lot) {
+	av.mu.Lock()
+	defer av.mu.Unlock()
+	fullLotIdx := lotIdx(av.lots, lot)
+	if fullLotIdx == -1 {
+		return
+	}
+	av.lots = append(av.lots[:fullLotIdx], av.lots[fullLotIdx+1:]...)
+}
+
+func (av *availability) markAvailable(lot *Lot) {
+	av.mu.Lock()
+	defer av.mu.Unlock()
+	if lotIdx(av.lots, lot) != -1 {
+		return
+	}
+	av.lots = append(av.lots, lot)
+}
+
+func (av *availability) within(scope []*Lot) []*Lot {
+	av.mu.Lock()
+	defer av.mu.Unlock()
+	output := make([]*Lot, 0, len(av.lots))
+	for _, l := range av.lots {
+		if lotIdx(scope, l) != -1 {
+			output = append(output, l)
+		}
+	}
+	return output
+}
+
+func lotIdx(lots []*Lot, lot *Lot) int {
+	output := -1
+	for idx, l := range lots {
+		if lot == l {
+			output = idx
+			break
+		}
+	}
+	return output
+}
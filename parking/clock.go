@@ -0,0 +1,14 @@
+package parking
+
+import "time"
+
+type Clock interface {
+	Now() time.Time
+}
+
+type SystemClock struct {
+}
+
+func (sc SystemClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,103 @@
+package parking_test
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/adityatresnobudi/parking-system/entity"
+	"github.com/adityatresnobudi/parking-system/parking"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrentGates(t *testing.T) {
+
+	t.Run("should never park more cars than total capacity when gates park concurrently", func(t *testing.T) {
+		lots := []*parking.Lot{parking.NewLot(10), parking.NewLot(20), parking.NewLot(5)}
+		a := parking.NewAttendant(lots)
+		a.ChangeStyle(&parking.HighestFreeSpace{})
+		var parked int64
+		var wg sync.WaitGroup
+
+		for gate := 0; gate < 20; gate++ {
+			wg.Add(1)
+			go func(gate int) {
+				defer wg.Done()
+				for i := 0; i < 10; i++ {
+					car := &entity.Car{PlateNumber: fmt.Sprintf("B %d %d", gate, i)}
+					if _, err := a.Park(car); err == nil {
+						atomic.AddInt64(&parked, 1)
+					}
+				}
+			}(gate)
+		}
+		wg.Wait()
+
+		assert.Equal(t, int64(35), parked)
+		for _, lot := range lots {
+			assert.Equal(t, 0, lot.FreeSpace())
+		}
+		assert.Empty(t, a.GetAvailLots())
+	})
+
+	t.Run("should keep capacity and available lots consistent when parking and unparking concurrently", func(t *testing.T) {
+		lots := []*parking.Lot{parking.NewLot(3), parking.NewLot(3)}
+		a := parking.NewAttendant(lots)
+		var wg sync.WaitGroup
+
+		for gate := 0; gate < 16; gate++ {
+			wg.Add(1)
+			go func(gate int) {
+				defer wg.Done()
+				for i := 0; i < 50; i++ {
+					car := &entity.Car{PlateNumber: fmt.Sprintf("B %d %d", gate, i)}
+					ticket, err := a.Park(car)
+					if err != nil {
+						continue
+					}
+					for _, lot := range lots {
+						assert.GreaterOrEqual(t, lot.FreeSpace(), 0)
+					}
+					_, err = a.UnPark(ticket)
+					assert.Nil(t, err)
+				}
+			}(gate)
+		}
+		wg.Wait()
+
+		assert.ElementsMatch(t, lots, a.GetAvailLots())
+		for _, lot := range lots {
+			assert.Equal(t, 3, lot.FreeSpace())
+		}
+	})
+
+	t.Run("should not over-fill a lot shared by two attendants", func(t *testing.T) {
+		lot := parking.NewLot(25)
+		a1 := parking.NewAttendant([]*parking.Lot{lot})
+		a2 := parking.NewAttendant([]*parking.Lot{lot})
+		var parked int64
+		var wg sync.WaitGroup
+
+		for gate := 0; gate < 10; gate++ {
+			wg.Add(1)
+			go func(gate int) {
+				defer wg.Done()
+				a := a1
+				if gate%2 == 0 {
+					a = a2
+				}
+				for i := 0; i < 10; i++ {
+					car := &entity.Car{PlateNumber: fmt.Sprintf("B %d %d", gate, i)}
+					if _, err := a.Park(car); err == nil {
+						atomic.AddInt64(&parked, 1)
+					}
+				}
+			}(gate)
+		}
+		wg.Wait()
+
+		assert.Equal(t, int64(25), parked)
+		assert.Equal(t, 0, lot.FreeSpace())
+	})
+}
@@ -0,0 +1,190 @@
+package parking
+
+import (
+	"sync"
+
+	"github.com/adityatresnobudi/parking-system/entity"
+)
+
+// Event is published on a lot's or an attendant's event bus. Source is the lot
+// the event happened in; it is nil for events an attendant publishes before
+// any lot is chosen.
+type Event interface {
+	Source() *Lot
+}
+
+type CarParked struct {
+	Lot    *Lot
+	Car    *entity.Car
+	Ticket entity.Ticket
+}
+
+type CarUnparked struct {
+	Lot    *Lot
+	Car    *entity.Car
+	Ticket entity.Ticket
+}
+
+// ParkRejected is published by the lot that refused the car, or by the
+// attendant with a nil Lot when no lot was tried.
+type ParkRejected struct {
+	Lot *Lot
+	Car *entity.Car
+	Err error
+}
+
+type LotFull struct {
+	Lot *Lot
+}
+
+type LotAvailable struct {
+	Lot *Lot
+}
+
+type LotClosed struct {
+	Lot *Lot
+}
+
+type LotReopened struct {
+	Lot *Lot
+}
+
+type CapacityChanged struct {
+	Lot       *Lot
+	Capacity  int
+	FreeSpace int
+}
+
+func (e CarParked) Source() *Lot       { return e.Lot }
+func (e CarUnparked) Source() *Lot     { return e.Lot }
+func (e ParkRejected) Source() *Lot    { return e.Lot }
+func (e LotFull) Source() *Lot         { return e.Lot }
+func (e LotAvailable) Source() *Lot    { return e.Lot }
+func (e LotClosed) Source() *Lot       { return e.Lot }
+func (e LotReopened) Source() *Lot     { return e.Lot }
+func (e CapacityChanged) Source() *Lot { return e.Lot }
+
+type EventHandler interface {
+	HandleEvent(Event)
+}
+
+type EventHandlerFunc func(Event)
+
+func (f EventHandlerFunc) HandleEvent(e Event) {
+	f(e)
+}
+
+type EventBus interface {
+	Subscribe(EventHandler)
+	Publish(Event)
+}
+
+// SyncBus calls every handler on the publishing goroutine. Lots publish
+// while holding their lock, so handlers must not call back into the lot;
+// subscribe through an AsyncBus for that.
+type SyncBus struct {
+	mu       sync.RWMutex
+	handlers []EventHandler
+}
+
+func NewSyncBus() *SyncBus {
+	return &SyncBus{handlers: make([]EventHandler, 0)}
+}
+
+func (sb *SyncBus) Subscribe(handler EventHandler) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	sb.handlers = append(sb.handlers, handler)
+}
+
+func (sb *SyncBus) Publish(e Event) {
+	sb.mu.RLock()
+	handlers := make([]EventHandler, len(sb.handlers))
+	copy(handlers, sb.handlers)
+	sb.mu.RUnlock()
+	for _, h := range handlers {
+		h.HandleEvent(e)
+	}
+}
+
+// AsyncBus hands events to a dispatch goroutine that calls the handlers, so
+// handlers may call back into the lot that published. Publish never blocks:
+// the queue grows past buffer as needed, and events published after Close are
+// dropped.
+type AsyncBus struct {
+	SyncBus
+	queueMu sync.Mutex
+	ready   *sync.Cond
+	queue   []Event
+	closed  bool
+	done    chan struct{}
+}
+
+func NewAsyncBus(buffer int) *AsyncBus {
+	if buffer < 0 {
+		buffer = 0
+	}
+	ab := &AsyncBus{
+		SyncBus: SyncBus{handlers: make([]EventHandler, 0)},
+		queue:   make([]Event, 0, buffer),
+		done:    make(chan struct{}),
+	}
+	ab.ready = sync.NewCond(&ab.queueMu)
+	go ab.dispatch()
+	return ab
+}
+
+func (ab *AsyncBus) Publish(e Event) {
+	ab.queueMu.Lock()
+	defer ab.queueMu.Unlock()
+	if ab.closed {
+		return
+	}
+	ab.queue = append(ab.queue, e)
+	ab.ready.Signal()
+}
+
+func (ab *AsyncBus) HandleEvent(e Event) {
+	ab.Publish(e)
+}
+
+// Close stops accepting events and waits until the queued ones are delivered.
+func (ab *AsyncBus) Close() {
+	ab.queueMu.Lock()
+	ab.closed = true
+	ab.ready.Signal()
+	ab.queueMu.Unlock()
+	<-ab.done
+}
+
+func (ab *AsyncBus) dispatch() {
+	defer close(ab.done)
+	for {
+		ab.queueMu.Lock()
+		for len(ab.queue) == 0 && !ab.closed {
+			ab.ready.Wait()
+		}
+		if len(ab.queue) == 0 {
+			ab.queueMu.Unlock()
+			return
+		}
+		e := ab.queue[0]
+		ab.queue[0] = nil
+		ab.queue = ab.queue[1:]
+		ab.queueMu.Unlock()
+		ab.SyncBus.Publish(e)
+	}
+}
+
+type subscriberAdapter struct {
+	sub Subscriber
+}
+
+func (sa subscriberAdapter) HandleEvent(e Event) {
+	switch e.(type) {
+	case LotFull:
+		sa.sub.NotifyLotIsFull(e.Source())
+	case LotAvailable:
+		sa.sub.NotifyLotIsNotFull(e.Source())
+	}
+}
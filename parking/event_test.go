@@ -0,0 +1,220 @@
+package parking_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adityatresnobudi/parking-system/entity"
+	"github.com/adityatresnobudi/parking-system/parking"
+	"github.com/stretchr/testify/assert"
+)
+
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []parking.Event
+}
+
+func (er *eventRecorder) HandleEvent(e parking.Event) {
+	er.mu.Lock()
+	defer er.mu.Unlock()
+	er.events = append(er.events, e)
+}
+
+func (er *eventRecorder) recorded() []parking.Event {
+	er.mu.Lock()
+	defer er.mu.Unlock()
+	return append([]parking.Event(nil), er.events...)
+}
+
+func TestEventBus(t *testing.T) {
+
+	t.Run("should deliver events to every handler in publish order on SyncBus", func(t *testing.T) {
+		bus := parking.NewSyncBus()
+		r1 := &eventRecorder{}
+		r2 := &eventRecorder{}
+		bus.Subscribe(r1)
+		bus.Subscribe(r2)
+
+		bus.Publish(parking.LotFull{})
+		bus.Publish(parking.LotAvailable{})
+
+		assert.Equal(t, []parking.Event{parking.LotFull{}, parking.LotAvailable{}}, r1.recorded())
+		assert.Equal(t, r1.recorded(), r2.recorded())
+	})
+
+	t.Run("should deliver buffered events asynchronously on AsyncBus", func(t *testing.T) {
+		bus := parking.NewAsyncBus(10)
+		recorder := &eventRecorder{}
+		bus.Subscribe(recorder)
+
+		for i := 0; i < 5; i++ {
+			bus.Publish(parking.CapacityChanged{Capacity: i})
+		}
+		bus.Close()
+
+		assert.Len(t, recorder.recorded(), 5)
+		assert.Equal(t, parking.CapacityChanged{Capacity: 4}, recorder.recorded()[4])
+	})
+
+	t.Run("should let handlers call back into lot when subscribed through AsyncBus", func(t *testing.T) {
+		lot := parking.NewLot(1)
+		bus := parking.NewAsyncBus(10)
+		freeSpaces := make(chan int, 10)
+		bus.Subscribe(parking.EventHandlerFunc(func(e parking.Event) {
+			freeSpaces <- e.Source().FreeSpace()
+		}))
+		lot.Events().Subscribe(bus)
+
+		_, _ = lot.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		bus.Close()
+
+		select {
+		case free := <-freeSpaces:
+			assert.Equal(t, 0, free)
+		case <-time.After(time.Second):
+			t.Fatal("no event delivered")
+		}
+	})
+
+	t.Run("should not block lot publishing when AsyncBus has no buffer", func(t *testing.T) {
+		lot := parking.NewLot(3)
+		bus := parking.NewAsyncBus(0)
+		recorder := &eventRecorder{}
+		bus.Subscribe(parking.EventHandlerFunc(func(e parking.Event) {
+			_ = e.Source().FreeSpace()
+			recorder.HandleEvent(e)
+		}))
+		lot.Events().Subscribe(bus)
+
+		for _, plate := range []string{"T 1 ST", "T 2 ST", "T 3 ST"} {
+			_, _ = lot.Park(&entity.Car{PlateNumber: plate})
+		}
+		bus.Close()
+
+		assert.Len(t, recorder.recorded(), 4)
+	})
+
+	t.Run("should drop events published after AsyncBus is closed", func(t *testing.T) {
+		bus := parking.NewAsyncBus(1)
+		recorder := &eventRecorder{}
+		bus.Subscribe(recorder)
+		bus.Close()
+
+		assert.NotPanics(t, func() { bus.Publish(parking.LotFull{}) })
+		assert.Empty(t, recorder.recorded())
+	})
+}
+
+func TestLotEvents(t *testing.T) {
+
+	t.Run("should publish car parked and lot full when last space is taken", func(t *testing.T) {
+		lot := parking.NewLot(1)
+		recorder := &eventRecorder{}
+		lot.Events().Subscribe(recorder)
+		car := &entity.Car{PlateNumber: "T 3 ST"}
+
+		ticket, _ := lot.Park(car)
+
+		assert.Equal(t, []parking.Event{
+			parking.CarParked{Lot: lot, Car: car, Ticket: *ticket},
+			parking.LotFull{Lot: lot},
+		}, recorder.recorded())
+	})
+
+	t.Run("should publish car unparked and lot available when full lot frees a space", func(t *testing.T) {
+		lot := parking.NewLot(1)
+		car := &entity.Car{PlateNumber: "T 3 ST"}
+		ticket, _ := lot.Park(car)
+		recorder := &eventRecorder{}
+		lot.Events().Subscribe(recorder)
+
+		_, _ = lot.UnPark(ticket)
+
+		assert.Equal(t, []parking.Event{
+			parking.CarUnparked{Lot: lot, Car: car, Ticket: *ticket},
+			parking.LotAvailable{Lot: lot},
+		}, recorder.recorded())
+	})
+
+	t.Run("should publish park rejected with the error when lot refuses car", func(t *testing.T) {
+		lot := parking.NewLot(1)
+		_, _ = lot.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		recorder := &eventRecorder{}
+		lot.Events().Subscribe(recorder)
+		car := &entity.Car{PlateNumber: "P O LE"}
+
+		_, _ = lot.Park(car)
+
+		assert.Equal(t, []parking.Event{
+			parking.ParkRejected{Lot: lot, Car: car, Err: parking.ErrUnavailablePosition},
+		}, recorder.recorded())
+	})
+
+	t.Run("should publish capacity changed when reservation holds a space", func(t *testing.T) {
+		lot := parking.NewLot(2)
+		a := parking.NewAttendant([]*parking.Lot{lot})
+		recorder := &eventRecorder{}
+		lot.Events().Subscribe(recorder)
+		now := time.Now()
+
+		_, _ = a.Reserve("1", &entity.Car{PlateNumber: "T 3 ST"}, now.Add(-time.Minute), now.Add(time.Hour))
+
+		assert.Equal(t, []parking.Event{
+			parking.CapacityChanged{Lot: lot, Capacity: 2, FreeSpace: 1},
+		}, recorder.recorded())
+	})
+}
+
+func TestAttendantEvents(t *testing.T) {
+
+	t.Run("should forward lot events to attendant subscribers", func(t *testing.T) {
+		lot := parking.NewLot(1)
+		a := parking.NewAttendant([]*parking.Lot{lot})
+		recorder := &eventRecorder{}
+		a.Events().Subscribe(recorder)
+		car := &entity.Car{PlateNumber: "T 3 ST"}
+
+		ticket, _ := a.Park(car)
+		_, _ = a.UnPark(ticket)
+
+		assert.Equal(t, []parking.Event{
+			parking.CarParked{Lot: lot, Car: car, Ticket: *ticket},
+			parking.LotFull{Lot: lot},
+			parking.CarUnparked{Lot: lot, Car: car, Ticket: *ticket},
+			parking.LotAvailable{Lot: lot},
+		}, recorder.recorded())
+	})
+
+	t.Run("should publish park rejected when attendant refuses car", func(t *testing.T) {
+		a := parking.NewAttendant([]*parking.Lot{parking.NewLot(1)})
+		recorder := &eventRecorder{}
+		car := &entity.Car{PlateNumber: "T 3 ST"}
+		_, _ = a.Park(car)
+		a.Events().Subscribe(recorder)
+
+		_, _ = a.Park(car)
+		_, _ = a.Park(&entity.Car{PlateNumber: "P O LE"})
+
+		assert.Equal(t, []parking.Event{
+			parking.ParkRejected{Car: car, Err: parking.ErrParkedCarTwice},
+			parking.ParkRejected{Car: &entity.Car{PlateNumber: "P O LE"}, Err: parking.ErrUnavailablePosition},
+		}, recorder.recorded())
+		for _, e := range recorder.recorded() {
+			assert.Nil(t, e.Source())
+		}
+	})
+
+	t.Run("should track available lots from lot events", func(t *testing.T) {
+		l1 := parking.NewLot(1)
+		l2 := parking.NewLot(1)
+		a := parking.NewAttendant([]*parking.Lot{l1, l2})
+
+		ticket, _ := a.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		afterPark := a.GetAvailLots()
+		_, _ = a.UnPark(ticket)
+
+		assert.Equal(t, []*parking.Lot{l2}, afterPark)
+		assert.ElementsMatch(t, []*parking.Lot{l1, l2}, a.GetAvailLots())
+	})
+}
@@ -0,0 +1,125 @@
+package parking
+
+import "sync"
+
+type Garage struct {
+	mu           sync.Mutex
+	attendantsMu sync.Mutex
+	lots         []*Lot
+	avail        *availability
+	attendants   []*Attendant
+}
+
+func NewGarage(lots []*Lot) *Garage {
+	assignLotIDs(lots)
+	return &Garage{
+		lots:       lots,
+		avail:      newAvailability(lots),
+		attendants: make([]*Attendant, 0),
+	}
+}
+
+func (g *Garage) NewAttendant(style LotSelector, lotIDs ...string) (*Attendant, error) {
+	g.mu.Lock()
+	scope, err := g.scope(lotIDs)
+	g.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	a := newAttendant(scope, g.avail, &g.mu)
+	a.garage = g
+	a.scoped = len(lotIDs) > 0
+	if style != nil {
+		a.ChangeStyle(style)
+	}
+
+	g.attendantsMu.Lock()
+	defer g.attendantsMu.Unlock()
+	g.attendants = append(g.attendants, a)
+	return a, nil
+}
+
+func (g *Garage) scope(lotIDs []string) ([]*Lot, error) {
+	scope := append([]*Lot(nil), g.lots...)
+	if len(lotIDs) > 0 {
+		scope = make([]*Lot, 0, len(lotIDs))
+		for _, id := range lotIDs {
+			lot, err := findLot(g.lots, id)
+			if err != nil {
+				return nil, err
+			}
+			if lotIdx(scope, lot) == -1 {
+				scope = append(scope, lot)
+			}
+		}
+	}
+	return scope, nil
+}
+
+// addLot must be called with g.mu held, which is the mutex shared by the
+// garage's attendants. The lot takes the ticket issuer and clock of by, and
+// goes to by and to every attendant that was not given a lot list.
+func (g *Garage) addLot(by *Attendant, lot *Lot) (string, error) {
+	if lotIdx(g.lots, lot) != -1 {
+		return lot.ID(), nil
+	}
+	lots := append(append([]*Lot(nil), g.lots...), lot)
+	if err := checkLotIDs(lots); err != nil {
+		return "", err
+	}
+	assignLotIDs(lots)
+	lot.SetTicketIssuer(by.issuer)
+	lot.SetClock(by.clock)
+
+	previous := by.lotList
+	by.lotList = append(append([]*Lot(nil), by.lotList...), lot)
+	if err := by.saveGarage(); err != nil {
+		by.lotList = previous
+		return "", err
+	}
+	g.lots = lots
+	g.avail.track(lot)
+	for _, a := range g.Attendants() {
+		if a != by && a.scoped {
+			continue
+		}
+		if a != by {
+			a.lotList = append(append([]*Lot(nil), a.lotList...), lot)
+		}
+		lot.Events().Subscribe(a)
+	}
+	return lot.ID(), nil
+}
+
+func (g *Garage) Attendants() []*Attendant {
+	g.attendantsMu.Lock()
+	defer g.attendantsMu.Unlock()
+	output := make([]*Attendant, len(g.attendants))
+	copy(output, g.attendants)
+	return output
+}
+
+func (g *Garage) Lots() []*Lot {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	output := make([]*Lot, len(g.lots))
+	copy(output, g.lots)
+	return output
+}
+
+func (g *Garage) GetAvailLots() []*Lot {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.avail.within(g.lots)
+}
+
+func (g *Garage) Status() []LotStatus {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	output := make([]LotStatus, 0, len(g.lots))
+	for _, lot := range g.lots {
+		output = append(output, lot.Status())
+	}
+	return output
+}
@@ -0,0 +1,145 @@
+package parking_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/adityatresnobudi/parking-system/entity"
+	"github.com/adityatresnobudi/parking-system/parking"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGarage(t *testing.T) {
+
+	t.Run("should return error when attendant is assigned an unknown lot", func(t *testing.T) {
+		g := parking.NewGarage([]*parking.Lot{parking.NewLot(1)})
+
+		a, err := g.NewAttendant(nil, "2")
+
+		assert.Nil(t, a)
+		assert.ErrorIs(t, err, parking.ErrUnknownLot)
+	})
+
+	t.Run("should only park in the lots assigned to the attendant", func(t *testing.T) {
+		l1, l2 := parking.NewLot(1), parking.NewLot(1)
+		g := parking.NewGarage([]*parking.Lot{l1, l2})
+		a, _ := g.NewAttendant(nil, "2")
+
+		_, err1 := a.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		_, err2 := a.Park(&entity.Car{PlateNumber: "P O LE"})
+
+		assert.Nil(t, err1)
+		assert.ErrorIs(t, err2, parking.ErrUnavailablePosition)
+		assert.Equal(t, 1, l1.FreeSpace())
+		assert.Equal(t, []*parking.Lot{l1}, g.GetAvailLots())
+	})
+
+	t.Run("should add lot to garage and every attendant when attendant adds a lot", func(t *testing.T) {
+		g := parking.NewGarage([]*parking.Lot{parking.NewLot(1)})
+		a1, _ := g.NewAttendant(nil)
+		a2, _ := g.NewAttendant(nil)
+		added := parking.NewLot(1)
+
+		id, err := a1.AddLot(added)
+		_, _ = a1.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		ticket, _ := a1.Park(&entity.Car{PlateNumber: "B 1 X"})
+		_, twiceErr := a2.Park(&entity.Car{PlateNumber: "B 1 X"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "2", id)
+		assert.Equal(t, "2", ticket.Lot)
+		assert.ErrorIs(t, twiceErr, parking.ErrParkedCarTwice)
+		assert.Equal(t, []*parking.Lot{g.Lots()[0], added}, g.Lots())
+		assert.Len(t, g.Status(), 2)
+		assert.Len(t, a2.Status(), 2)
+	})
+
+	t.Run("should not hand an added lot to attendants limited to other lots", func(t *testing.T) {
+		g := parking.NewGarage([]*parking.Lot{parking.NewLot(1), parking.NewLot(1)})
+		adder, _ := g.NewAttendant(nil, "2")
+		limited, _ := g.NewAttendant(nil, "1")
+		unlimited, _ := g.NewAttendant(nil)
+
+		_, err := adder.AddLot(parking.NewLot(1))
+		_, _ = limited.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		_, rejected := limited.Park(&entity.Car{PlateNumber: "P O LE"})
+
+		assert.Nil(t, err)
+		assert.Len(t, adder.Status(), 2)
+		assert.Len(t, limited.Status(), 1)
+		assert.Len(t, unlimited.Status(), 3)
+		assert.ErrorIs(t, rejected, parking.ErrUnavailablePosition)
+	})
+
+	t.Run("should use each attendant's own parking style", func(t *testing.T) {
+		l1, l2 := parking.NewLot(1), parking.NewLot(3)
+		g := parking.NewGarage([]*parking.Lot{l1, l2})
+		first, _ := g.NewAttendant(&parking.FirstAvailable{})
+		highest, _ := g.NewAttendant(&parking.HighestCapacity{})
+
+		_, _ = highest.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		_, _ = first.Park(&entity.Car{PlateNumber: "P O LE"})
+
+		assert.Equal(t, 0, l1.FreeSpace())
+		assert.Equal(t, 2, l2.FreeSpace())
+	})
+
+	t.Run("should share availability across attendants", func(t *testing.T) {
+		l1, l2 := parking.NewLot(1), parking.NewLot(1)
+		g := parking.NewGarage([]*parking.Lot{l1, l2})
+		a1, _ := g.NewAttendant(nil)
+		a2, _ := g.NewAttendant(nil, "1")
+
+		ticket, _ := a1.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		full := a2.GetAvailLots()
+		_, _ = a1.UnPark(ticket)
+
+		assert.Empty(t, full)
+		assert.Equal(t, []*parking.Lot{l1}, a2.GetAvailLots())
+		assert.ElementsMatch(t, []*parking.Lot{l1, l2}, a1.GetAvailLots())
+	})
+
+	t.Run("should reject a car parked by another attendant of the garage", func(t *testing.T) {
+		g := parking.NewGarage([]*parking.Lot{parking.NewLot(1), parking.NewLot(1)})
+		a1, _ := g.NewAttendant(nil, "1")
+		a2, _ := g.NewAttendant(nil, "2")
+
+		_, _ = a1.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		ticket, err := a2.Park(&entity.Car{PlateNumber: "T 3 ST"})
+
+		assert.Nil(t, ticket)
+		assert.ErrorIs(t, err, parking.ErrParkedCarTwice)
+	})
+
+	t.Run("should list attendants in the order they were added", func(t *testing.T) {
+		g := parking.NewGarage([]*parking.Lot{parking.NewLot(1)})
+		a1, _ := g.NewAttendant(nil)
+		a2, _ := g.NewAttendant(nil)
+
+		assert.Equal(t, []*parking.Attendant{a1, a2}, g.Attendants())
+	})
+
+	t.Run("should never park more cars than capacity when attendants park concurrently", func(t *testing.T) {
+		lots := []*parking.Lot{parking.NewLot(10), parking.NewLot(5)}
+		g := parking.NewGarage(lots)
+		var wg sync.WaitGroup
+
+		for i := 0; i < 4; i++ {
+			a, _ := g.NewAttendant(&parking.HighestFreeSpace{})
+			wg.Add(1)
+			go func(gate int, a *parking.Attendant) {
+				defer wg.Done()
+				for i := 0; i < 10; i++ {
+					_, _ = a.Park(&entity.Car{PlateNumber: fmt.Sprintf("B %d %d", gate, i)})
+				}
+			}(i, a)
+		}
+		wg.Wait()
+
+		for _, lot := range lots {
+			assert.Equal(t, 0, lot.FreeSpace())
+		}
+		assert.Empty(t, g.GetAvailLots())
+	})
+}
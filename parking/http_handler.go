@@ -0,0 +1,232 @@
+package parking
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adityatresnobudi/parking-system/entity"
+	"github.com/adityatresnobudi/parking-system/plate"
+)
+
+type HTTPServer struct {
+	mu        sync.RWMutex
+	attendant *Attendant
+	repo      Repository
+	configure []func(*Attendant) error
+	mux       *http.ServeMux
+}
+
+type setupRequest struct {
+	Capacities []int `json:"capacities"`
+}
+
+type parkRequest struct {
+	PlateNumber string   `json:"plate_number"`
+	VehicleType string   `json:"vehicle_type"`
+	Permits     []string `json:"permits"`
+}
+
+type ticketResponse struct {
+	TicketID  string    `json:"ticket_id"`
+	Lot       string    `json:"lot"`
+	Space     string    `json:"space"`
+	EntryTime time.Time `json:"entry_time"`
+}
+
+type receiptResponse struct {
+	TicketID        string    `json:"ticket_id"`
+	PlateNumber     string    `json:"plate_number"`
+	EntryTime       time.Time `json:"entry_time"`
+	ExitTime        time.Time `json:"exit_time"`
+	DurationSeconds int64     `json:"duration_seconds"`
+	Amount          int       `json:"amount"`
+}
+
+type errorResponse struct {
+	Error errorBody `json:"error"`
+}
+
+type errorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+var httpErrors = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{ErrInvalidInput, http.StatusBadRequest, "invalid_input"},
+	{ErrInvalidCapacity, http.StatusBadRequest, "invalid_capacity"},
+	{ErrDuplicateLotID, http.StatusBadRequest, "duplicate_lot_id"},
+	{ErrNoParkingLot, http.StatusConflict, "no_parking_lot"},
+	{ErrUnavailablePosition, http.StatusConflict, "no_available_position"},
+	{ErrParkedCarTwice, http.StatusConflict, "car_already_inside"},
+	{ErrUnrecognizedParkingTicket, http.StatusNotFound, "unrecognized_parking_ticket"},
+	{ErrCarNotFound, http.StatusNotFound, "car_not_found"},
+	{ErrUnknownLot, http.StatusNotFound, "unknown_lot"},
+	{ErrVehicleNotAccepted, http.StatusUnprocessableEntity, "vehicle_not_accepted"},
+	{ErrLotClosed, http.StatusConflict, "lot_closed"},
+	{entity.ErrUnknownVehicleType, http.StatusBadRequest, "unknown_vehicle_type"},
+	{entity.ErrUnknownPermit, http.StatusBadRequest, "unknown_permit"},
+	{plate.ErrInvalidPlate, http.StatusBadRequest, "invalid_plate"},
+}
+
+// NewHTTPServer serves the JSON API for attendant. Every configure hook runs on
+// each attendant the server creates, e.g. to resume an audit trail or apply a
+// loaded config after POST /lots.
+func NewHTTPServer(attendant *Attendant, repo Repository, configure ...func(*Attendant) error) *HTTPServer {
+	s := &HTTPServer{
+		attendant: attendant,
+		repo:      repo,
+		configure: configure,
+		mux:       http.NewServeMux(),
+	}
+	s.mux.HandleFunc("/lots", s.handleSetup)
+	s.mux.HandleFunc("/park", s.handlePark)
+	s.mux.HandleFunc("/unpark/", s.handleUnPark)
+	s.mux.HandleFunc("/status", s.handleStatus)
+	return s
+}
+
+func (s *HTTPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *HTTPServer) currentAttendant() *Attendant {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.attendant
+}
+
+func (s *HTTPServer) handleSetup(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	var req setupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, ErrInvalidInput)
+		return
+	}
+
+	capacities := make([]string, 0, len(req.Capacities))
+	for _, c := range req.Capacities {
+		capacities = append(capacities, strconv.Itoa(c))
+	}
+	attendant, err := SetupHandler(strings.Join(capacities, ","), s.repo)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	for _, configure := range s.configure {
+		if err := configure(attendant); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+
+	s.mu.Lock()
+	s.attendant = attendant
+	s.mu.Unlock()
+	writeJSON(w, http.StatusCreated, lotStatuses(attendant))
+}
+
+func (s *HTTPServer) handlePark(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	var req parkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, ErrInvalidInput)
+		return
+	}
+
+	permits, err := entity.ParsePermits(strings.Join(req.Permits, ","))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	ticket, err := parkCar(req.PlateNumber, req.VehicleType, permits, s.currentAttendant())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, ticketResponse{TicketID: ticket.ID, Lot: ticket.Lot, Space: ticket.Space, EntryTime: ticket.EntryTime})
+}
+
+func (s *HTTPServer) handleUnPark(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	ticketID := strings.TrimPrefix(r.URL.Path, "/unpark/")
+
+	receipt, err := unParkCar(ticketID, s.currentAttendant())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, receiptResponse{
+		TicketID:        receipt.Ticket.ID,
+		PlateNumber:     receipt.Car.PlateNumber,
+		EntryTime:       receipt.EntryTime,
+		ExitTime:        receipt.ExitTime,
+		DurationSeconds: int64(receipt.Duration / time.Second),
+		Amount:          receipt.Amount,
+	})
+}
+
+func (s *HTTPServer) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	attendant := s.currentAttendant()
+	if !isAttendantExist(attendant) {
+		writeError(w, ErrNoParkingLot)
+		return
+	}
+	statuses := attendant.Status()
+	if err := SortStatus(statuses, r.URL.Query().Get("sort")); err != nil {
+		writeError(w, fmt.Errorf("%w: %v", ErrInvalidInput, err))
+		return
+	}
+	writeJSON(w, http.StatusOK, statusResponses(statuses))
+}
+
+func lotStatuses(attendant *Attendant) []lotStatusResponse {
+	return statusResponses(attendant.Status())
+}
+
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: errorBody{
+		Code:    "method_not_allowed",
+		Message: "method not allowed",
+	}})
+	return false
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	for _, e := range httpErrors {
+		if errors.Is(err, e.err) {
+			writeJSON(w, e.status, errorResponse{Error: errorBody{Code: e.code, Message: err.Error()}})
+			return
+		}
+	}
+	writeJSON(w, http.StatusInternalServerError, errorResponse{Error: errorBody{Code: "internal_error", Message: err.Error()}})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,189 @@
+package parking_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adityatresnobudi/parking-system/entity"
+	"github.com/adityatresnobudi/parking-system/parking"
+	"github.com/adityatresnobudi/parking-system/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func doRequest(handler http.Handler, method string, path string, body string) (*httptest.ResponseRecorder, map[string]any) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	var decoded map[string]any
+	_ = json.Unmarshal(rec.Body.Bytes(), &decoded)
+	return rec, decoded
+}
+
+func errorCode(body map[string]any) string {
+	errBody, _ := body["error"].(map[string]any)
+	code, _ := errBody["code"].(string)
+	return code
+}
+
+func TestHTTPServer(t *testing.T) {
+
+	t.Run("should create lots when POST /lots", func(t *testing.T) {
+		server := parking.NewHTTPServer(nil, nil)
+
+		rec, _ := doRequest(server, http.MethodPost, "/lots", `{"capacities":[1,2]}`)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.JSONEq(t, `[{"lot":"1","free_space":1,"free_by_type":{"motorcycle":1,"car":1,"van":0,"bus":0},"parked_cars":[]},`+
+			`{"lot":"2","free_space":2,"free_by_type":{"motorcycle":2,"car":2,"van":1,"bus":0},"parked_cars":[]}]`, rec.Body.String())
+	})
+
+	t.Run("should configure attendant created by POST /lots", func(t *testing.T) {
+		journal, _ := storage.NewJournal(filepath.Join(t.TempDir(), "journal.jsonl"))
+		server := parking.NewHTTPServer(nil, nil, func(a *parking.Attendant) error {
+			return a.ResumeAuditTrail(journal)
+		})
+
+		_, _ = doRequest(server, http.MethodPost, "/lots", `{"capacities":[1]}`)
+		_, parked := doRequest(server, http.MethodPost, "/park", `{"plate_number":"B 3 ST"}`)
+		events, err := journal.Events()
+
+		assert.Nil(t, err)
+		assert.Len(t, events, 1)
+		assert.Equal(t, parking.AuditPark, events[0].Action)
+		assert.Equal(t, parked["ticket_id"], events[0].TicketID)
+	})
+
+	t.Run("should return bad request when POST /lots with empty capacities", func(t *testing.T) {
+		server := parking.NewHTTPServer(nil, nil)
+
+		rec, body := doRequest(server, http.MethodPost, "/lots", `{"capacities":[]}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "invalid_input", errorCode(body))
+	})
+
+	t.Run("should return bad request when POST /lots with zero capacity", func(t *testing.T) {
+		server := parking.NewHTTPServer(nil, nil)
+
+		rec, body := doRequest(server, http.MethodPost, "/lots", `{"capacities":[0]}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "invalid_capacity", errorCode(body))
+	})
+
+	t.Run("should return conflict when parking before lots are set up", func(t *testing.T) {
+		server := parking.NewHTTPServer(nil, nil)
+
+		rec, body := doRequest(server, http.MethodPost, "/park", `{"plate_number":"B 3 ST"}`)
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, "no_parking_lot", errorCode(body))
+	})
+
+	t.Run("should return ticket when POST /park", func(t *testing.T) {
+		server := parking.NewHTTPServer(parking.NewAttendant([]*parking.Lot{parking.NewLot(1)}), nil)
+
+		rec, body := doRequest(server, http.MethodPost, "/park", `{"plate_number":"B 3 ST"}`)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.NotEmpty(t, body["ticket_id"])
+	})
+
+	t.Run("should return conflict when POST /park with car already inside", func(t *testing.T) {
+		server := parking.NewHTTPServer(parking.NewAttendant([]*parking.Lot{parking.NewLot(2)}), nil)
+
+		_, _ = doRequest(server, http.MethodPost, "/park", `{"plate_number":"B 3 ST"}`)
+		rec, body := doRequest(server, http.MethodPost, "/park", `{"plate_number":"B 3 ST"}`)
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, "car_already_inside", errorCode(body))
+	})
+
+	t.Run("should return bad request when POST /park with invalid plate", func(t *testing.T) {
+		server := parking.NewHTTPServer(parking.NewAttendant([]*parking.Lot{parking.NewLot(1)}), nil)
+
+		rec, body := doRequest(server, http.MethodPost, "/park", `{"plate_number":"B 12#3"}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "invalid_plate", errorCode(body))
+	})
+
+	t.Run("should return conflict when POST /park with no available position", func(t *testing.T) {
+		server := parking.NewHTTPServer(parking.NewAttendant([]*parking.Lot{parking.NewLot(1)}), nil)
+
+		_, _ = doRequest(server, http.MethodPost, "/park", `{"plate_number":"B 3 ST"}`)
+		rec, body := doRequest(server, http.MethodPost, "/park", `{"plate_number":"P O LE"}`)
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, "no_available_position", errorCode(body))
+	})
+
+	t.Run("should return unprocessable entity when POST /park with vehicle no lot accepts", func(t *testing.T) {
+		lot := parking.NewLotWithVehicles(5, map[entity.VehicleType]int{entity.VehicleCar: 1})
+		server := parking.NewHTTPServer(parking.NewAttendant([]*parking.Lot{lot}), nil)
+
+		rec, body := doRequest(server, http.MethodPost, "/park", `{"plate_number":"B 3 ST","vehicle_type":"bus"}`)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Equal(t, "vehicle_not_accepted", errorCode(body))
+	})
+
+	t.Run("should return bad request when POST /park with malformed body", func(t *testing.T) {
+		server := parking.NewHTTPServer(parking.NewAttendant([]*parking.Lot{parking.NewLot(1)}), nil)
+
+		rec, body := doRequest(server, http.MethodPost, "/park", `{`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "invalid_input", errorCode(body))
+	})
+
+	t.Run("should return receipt when POST /unpark/{ticket}", func(t *testing.T) {
+		server := parking.NewHTTPServer(parking.NewAttendant([]*parking.Lot{parking.NewLot(1)}), nil)
+		_, parked := doRequest(server, http.MethodPost, "/park", `{"plate_number":"B 3 ST"}`)
+
+		rec, body := doRequest(server, http.MethodPost, "/unpark/"+parked["ticket_id"].(string), "")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "B 3 ST", body["plate_number"])
+		assert.Equal(t, float64(0), body["amount"])
+	})
+
+	t.Run("should return not found when POST /unpark/{ticket} with unknown ticket", func(t *testing.T) {
+		server := parking.NewHTTPServer(parking.NewAttendant([]*parking.Lot{parking.NewLot(1)}), nil)
+
+		rec, body := doRequest(server, http.MethodPost, "/unpark/ERR", "")
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, "unrecognized_parking_ticket", errorCode(body))
+	})
+
+	t.Run("should return parked cars when GET /status", func(t *testing.T) {
+		attendant := parking.NewAttendant([]*parking.Lot{parking.NewLot(2)})
+		attendant.SetClock(&fakeClock{now: time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC)})
+		server := parking.NewHTTPServer(attendant, nil)
+		_, parked := doRequest(server, http.MethodPost, "/park", `{"plate_number":"B 3 ST"}`)
+
+		rec, _ := doRequest(server, http.MethodGet, "/status", "")
+
+		expected := `[{"lot":"1","free_space":1,"free_by_type":{"motorcycle":1,"car":1,"van":0,"bus":0},` +
+			`"parked_cars":[{"ticket_id":"` + parked["ticket_id"].(string) + `","plate_number":"B 3 ST","vehicle_type":"car","space":"1-A-01",` +
+			`"entry_time":"2023-01-02T09:00:00Z"}]}]`
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, expected, rec.Body.String())
+	})
+
+	t.Run("should return method not allowed when GET /park", func(t *testing.T) {
+		server := parking.NewHTTPServer(nil, nil)
+
+		rec, body := doRequest(server, http.MethodGet, "/park", "")
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+		assert.Equal(t, http.MethodPost, rec.Header().Get("Allow"))
+		assert.Equal(t, "method_not_allowed", errorCode(body))
+	})
+}
@@ -5,16 +5,21 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/adityatresnobudi/parking-system/entity"
+	"github.com/adityatresnobudi/parking-system/plate"
 )
 
+const timeLayout = "2006-01-02 15:04"
+
 var (
 	ErrNoParkingLot = errors.New("parking lot haven't been setup")
 	ErrInvalidInput = errors.New("invalid input")
+	ErrNotConfirmed = errors.New("lost ticket release cancelled")
 )
 
-func SetupHandler(arg string) (*Attendant, error) {
+func SetupHandler(arg string, repo Repository) (*Attendant, error) {
 	if !isArgsValid(arg) {
 		return nil, ErrInvalidInput
 	}
@@ -22,38 +27,166 @@ func SetupHandler(arg string) (*Attendant, error) {
 	lots := make([]*Lot, 0)
 
 	for _, v := range strings.Split(arg, ",") {
-		capacity, err := strconv.Atoi(v)
+		lot, err := parseLotSpec(v)
 		if err != nil {
-			return nil, ErrInvalidInput
+			return nil, err
 		}
 
-		lots = append(lots, NewLot(capacity))
+		lots = append(lots, lot)
+	}
+	if err := checkLotIDs(lots); err != nil {
+		return nil, err
 	}
 
-	return NewAttendant(lots), nil
+	attendant := NewAttendant(lots)
+	if repo != nil {
+		if err := attendant.UseRepository(repo); err != nil {
+			return nil, err
+		}
+	}
+	return attendant, nil
 }
 
-func ParkHandler(arg string, attendant *Attendant) (string, error) {
-	if !isArgsValid(arg) {
+func parseLotSpec(spec string) (*Lot, error) {
+	id, size := "", spec
+	if i := strings.Index(spec, ":"); i != -1 {
+		id, size = strings.TrimSpace(spec[:i]), spec[i+1:]
+		if id == "" {
+			return nil, ErrInvalidInput
+		}
+	}
+	capacity, err := strconv.Atoi(strings.TrimSpace(size))
+	if err != nil {
+		return nil, ErrInvalidInput
+	}
+	if capacity < 1 {
+		return nil, ErrInvalidCapacity
+	}
+
+	lot := NewLot(capacity)
+	lot.SetInfo(LotInfo{ID: id})
+	return lot, nil
+}
+
+func RestoreHandler(repo Repository) (*Attendant, error) {
+	if repo == nil {
+		return nil, ErrNoSavedGarage
+	}
+	return RestoreAttendant(repo)
+}
+
+func ReserveHandler(lotID string, plateNumber string, vehicleType string, start string, end string, attendant *Attendant) (string, error) {
+	if !isArgsValid(lotID) || !isArgsValid(plateNumber) || !isArgsValid(start) || !isArgsValid(end) {
 		return "", ErrInvalidInput
 	}
 
+	vt, err := entity.ParseVehicleType(vehicleType)
+	if err != nil {
+		return "", err
+	}
+
 	if !isAttendantExist(attendant) {
 		return "", ErrNoParkingLot
 	}
 
-	car := &entity.Car{PlateNumber: arg}
-	ticket, err := attendant.Park(car)
-	switch err {
-	case ErrUnavailablePosition:
+	plateNumber, err = attendant.ValidatePlate(plateNumber)
+	if err != nil {
 		return "", err
-	case ErrParkedCarTwice:
+	}
+
+	from, err := time.ParseInLocation(timeLayout, start, time.Local)
+	if err != nil {
+		return "", ErrInvalidInput
+	}
+	until, err := time.ParseInLocation(timeLayout, end, time.Local)
+	if err != nil {
+		return "", ErrInvalidInput
+	}
+
+	r, err := attendant.Reserve(lotID, &entity.Car{PlateNumber: plateNumber, Type: vt}, from, until)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Space reserved for %s in lot #%s with reservation id %s", r.PlateNumber, lotID, r.ID), nil
+}
+
+func ParkHandler(arg string, attendant *Attendant) (string, error) {
+	return ParkVehicleHandler(arg, "", attendant)
+}
+
+func ParkVehicleHandler(arg string, vehicleType string, attendant *Attendant) (string, error) {
+	return ParkPermitHandler(arg, vehicleType, "", attendant)
+}
+
+func ParkPermitHandler(arg string, vehicleType string, permits string, attendant *Attendant) (string, error) {
+	parsed, err := entity.ParsePermits(permits)
+	if err != nil {
 		return "", err
 	}
-	return fmt.Sprintf("Car parked with ticket id %s", ticket.ID), nil
+	ticket, err := parkCar(arg, vehicleType, parsed, attendant)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Car parked with ticket id %s at lot #%s space %s", ticket.ID, ticket.Lot, ticket.Space), nil
+}
+
+func parkCar(arg string, vehicleType string, permits []entity.Permit, attendant *Attendant) (*entity.Ticket, error) {
+	if !isArgsValid(arg) {
+		return nil, ErrInvalidInput
+	}
+
+	vt, err := entity.ParseVehicleType(vehicleType)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isAttendantExist(attendant) {
+		return nil, ErrNoParkingLot
+	}
+
+	plateNumber, err := attendant.ValidatePlate(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	car := &entity.Car{PlateNumber: plateNumber, Type: vt, Permits: permits}
+	return attendant.Park(car)
 }
 
 func UnParkHandler(arg string, attendant *Attendant) (string, error) {
+	receipt, err := unParkCar(arg, attendant)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Car %s succesfully unparked!\n%s", receipt.Car.PlateNumber, formatReceipt(receipt)), nil
+}
+
+func unParkCar(arg string, attendant *Attendant) (*Receipt, error) {
+	if !isArgsValid(arg) {
+		return nil, ErrInvalidInput
+	}
+
+	if !isAttendantExist(attendant) {
+		return nil, ErrNoParkingLot
+	}
+
+	ticket := &entity.Ticket{ID: arg}
+	return attendant.UnPark(ticket)
+}
+
+func formatReceipt(receipt *Receipt) string {
+	duration := receipt.Duration.Round(time.Minute)
+	hours := int(duration.Hours())
+	minutes := int(duration.Minutes()) - hours*60
+	res := fmt.Sprintf("Duration: %dh %02dm\n", hours, minutes)
+	if receipt.Penalty > 0 {
+		res += fmt.Sprintf("Lost ticket penalty: %d\n", receipt.Penalty)
+	}
+	return res + fmt.Sprintf("Amount due: %d", receipt.Amount)
+}
+
+func LostTicketQuoteHandler(arg string, attendant *Attendant) (string, error) {
 	if !isArgsValid(arg) {
 		return "", ErrInvalidInput
 	}
@@ -62,13 +195,31 @@ func UnParkHandler(arg string, attendant *Attendant) (string, error) {
 		return "", ErrNoParkingLot
 	}
 
-	ticket := &entity.Ticket{ID: arg}
-	returnedCar, err := attendant.UnPark(ticket)
+	receipt, err := attendant.FindLostTicket(arg)
 	if err != nil {
 		return "", err
 	}
+	return fmt.Sprintf("Car %s found at lot #%s space %s\n%s", receipt.Car.PlateNumber, receipt.Ticket.Lot, receipt.Ticket.Space, formatReceipt(receipt)), nil
+}
+
+func LostTicketHandler(arg string, confirm string, attendant *Attendant) (string, error) {
+	if !isArgsValid(arg) {
+		return "", ErrInvalidInput
+	}
+
+	if !isAttendantExist(attendant) {
+		return "", ErrNoParkingLot
+	}
 
-	return fmt.Sprintf("Car %s succesfully unparked!", returnedCar.PlateNumber), nil
+	if !isConfirmed(confirm) {
+		return "", ErrNotConfirmed
+	}
+
+	receipt, err := attendant.ReleaseLostTicket(arg)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Car %s released without ticket!\n%s", receipt.Car.PlateNumber, formatReceipt(receipt)), nil
 }
 
 func StatusHandler(attendant *Attendant) (string, error) {
@@ -77,21 +228,257 @@ func StatusHandler(attendant *Attendant) (string, error) {
 	}
 
 	res := "Parking Lot Status:\n"
+	res += fmt.Sprintf("Parking style: %s\n", StyleName(attendant.Style()))
+
+	res += formatStatusText(attendant.Status())
+
+	return res, nil
+}
+
+func StatusFormatHandler(format string, order string, attendant *Attendant) (string, error) {
+	if !isAttendantExist(attendant) {
+		return "", ErrNoParkingLot
+	}
+
+	statuses := attendant.Status()
+	if err := SortStatus(statuses, order); err != nil {
+		return "", fmt.Errorf("%w: %s", err, order)
+	}
+	if format == FormatText || format == "" {
+		res := "Parking Lot Status:\n"
+		res += fmt.Sprintf("Parking style: %s\n", StyleName(attendant.Style()))
+		return res + formatStatusText(statuses), nil
+	}
+	var buf strings.Builder
+	if err := RenderStatus(&buf, statuses, format); err != nil {
+		return "", fmt.Errorf("%w: %s", err, format)
+	}
+	return strings.TrimRight(buf.String(), "\n"), nil
+}
+
+func formatLotInfo(info LotInfo) string {
+	res := "Lot #" + info.ID
+	if info.Name != "" {
+		res += " " + info.Name
+	}
+	if info.Location != "" {
+		res += " @ " + info.Location
+	}
+	if len(info.Tags) > 0 {
+		res += " [" + strings.Join(info.Tags, ", ") + "]"
+	}
+	return res
+}
 
-	for i, v := range attendant.Status() {
-		res += fmt.Sprintf("Lot #%d: %d spaces left\n", i+1, v.freeSpace)
-		for ticket, car := range v.parkedCars {
-			res += fmt.Sprintf("#%s %s\n", ticket, car.PlateNumber)
+func formatSpaces(spaces []Space) string {
+	res := ""
+	for i, space := range spaces {
+		if i == 0 || !space.sameRow(spaces[i-1]) {
+			if i > 0 {
+				res += "]\n"
+			}
+			res += fmt.Sprintf("%s-%s [", space.Level, space.Row)
+		} else {
+			res += " "
 		}
+		if space.IsFree() {
+			res += "."
+		} else {
+			res += "X"
+		}
+	}
+	if len(spaces) > 0 {
+		res += "]\n"
 	}
+	return res
+}
 
+func spaceOf(spaces []Space, ticketID string) string {
+	for _, space := range spaces {
+		if space.TicketID == ticketID {
+			return space.Label()
+		}
+	}
+	return ""
+}
+
+func formatFreeByType(freeByType map[entity.VehicleType]int) string {
+	parts := make([]string, 0, len(freeByType))
+	for _, vt := range entity.VehicleTypes {
+		if n, ok := freeByType[vt]; ok {
+			parts = append(parts, fmt.Sprintf("%s: %d", vt, n))
+		}
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+func AddLotHandler(arg string, attendant *Attendant) (string, error) {
+	if !isArgsValid(arg) {
+		return "", ErrInvalidInput
+	}
+
+	if !isAttendantExist(attendant) {
+		return "", ErrNoParkingLot
+	}
+
+	lot, err := parseLotSpec(arg)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := attendant.AddLot(lot)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Lot #%s added with %d spaces", id, lot.Capacity()), nil
+}
+
+func ResizeLotHandler(lotID string, arg string, attendant *Attendant) (string, error) {
+	if !isArgsValid(lotID) || !isArgsValid(arg) {
+		return "", ErrInvalidInput
+	}
+
+	if !isAttendantExist(attendant) {
+		return "", ErrNoParkingLot
+	}
+
+	capacity, err := strconv.Atoi(arg)
+	if err != nil {
+		return "", ErrInvalidInput
+	}
+
+	if err := attendant.ResizeLot(lotID, capacity); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Lot #%s resized to %d spaces", lotID, capacity), nil
+}
+
+func CloseLotHandler(lotID string, attendant *Attendant) (string, error) {
+	if !isArgsValid(lotID) {
+		return "", ErrInvalidInput
+	}
+
+	if !isAttendantExist(attendant) {
+		return "", ErrNoParkingLot
+	}
+
+	if err := attendant.CloseLot(lotID); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Lot #%s closed", lotID), nil
+}
+
+func ReopenLotHandler(lotID string, attendant *Attendant) (string, error) {
+	if !isArgsValid(lotID) {
+		return "", ErrInvalidInput
+	}
+
+	if !isAttendantExist(attendant) {
+		return "", ErrNoParkingLot
+	}
+
+	if err := attendant.ReopenLot(lotID); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Lot #%s reopened", lotID), nil
+}
+
+func HistoryHandler(arg string, from string, to string, attendant *Attendant) (string, error) {
+	if !isAttendantExist(attendant) {
+		return "", ErrNoParkingLot
+	}
+
+	query := AuditQuery{}
+	var err error
+	if from != "" {
+		if query.From, err = time.ParseInLocation(timeLayout, from, time.Local); err != nil {
+			return "", ErrInvalidInput
+		}
+	}
+	if to != "" {
+		if query.To, err = time.ParseInLocation(timeLayout, to, time.Local); err != nil {
+			return "", ErrInvalidInput
+		}
+	}
+
+	events, err := attendant.History(query)
+	if err != nil {
+		return "", err
+	}
+
+	res := "Parking History:"
+	for _, e := range events {
+		if arg != "" && !plate.Equal(e.PlateNumber, arg) && e.TicketID != arg {
+			continue
+		}
+		res += fmt.Sprintf("\n%s %s", e.Time.Format(timeLayout), e.Action)
+		if e.TicketID != "" {
+			res += fmt.Sprintf(" #%s", e.TicketID)
+		}
+		if e.PlateNumber != "" {
+			res += " " + e.PlateNumber
+		}
+		if e.Amount > 0 {
+			res += fmt.Sprintf(" %d", e.Amount)
+		}
+		if e.Error != "" {
+			res += fmt.Sprintf(" (%s)", e.Error)
+		}
+	}
+	return res, nil
+}
+
+func StyleListHandler(attendant *Attendant) (string, error) {
+	if !isAttendantExist(attendant) {
+		return "", ErrNoParkingLot
+	}
+
+	current := StyleName(attendant.Style())
+	res := "Parking styles:"
+	for i, name := range StyleNames() {
+		res += fmt.Sprintf("\n%d. %s", i+1, name)
+		if name == current {
+			res += " (current)"
+		}
+	}
 	return res, nil
 }
 
+func ChangeStyleHandler(arg string, attendant *Attendant) (string, error) {
+	if !isArgsValid(arg) {
+		return "", ErrInvalidInput
+	}
+
+	if !isAttendantExist(attendant) {
+		return "", ErrNoParkingLot
+	}
+
+	name := arg
+	if idx, err := strconv.Atoi(arg); err == nil {
+		names := StyleNames()
+		if idx < 1 || idx > len(names) {
+			return "", ErrUnknownStyle
+		}
+		name = names[idx-1]
+	}
+
+	style, err := NewStyle(name)
+	if err != nil {
+		return "", err
+	}
+	attendant.ChangeStyle(style)
+	return fmt.Sprintf("Parking style changed to %s", name), nil
+}
+
 func isAttendantExist(attendant *Attendant) bool {
 	return attendant != nil
 }
 
+func isConfirmed(arg string) bool {
+	arg = strings.ToLower(strings.TrimSpace(arg))
+	return arg == "y" || arg == "yes"
+}
+
 func isArgsValid(arg string) bool {
 	return arg != ""
 }
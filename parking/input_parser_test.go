@@ -3,9 +3,11 @@ package parking_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/adityatresnobudi/parking-system/entity"
 	"github.com/adityatresnobudi/parking-system/parking"
+	"github.com/adityatresnobudi/parking-system/plate"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -14,7 +16,7 @@ func TestUtility(t *testing.T) {
 	t.Run("should return error when given invalid SetupHandler arguments", func(t *testing.T) {
 		arg := ""
 
-		attendant, err := parking.SetupHandler(arg)
+		attendant, err := parking.SetupHandler(arg, nil)
 
 		assert.ErrorIs(t, parking.ErrInvalidInput, err)
 		assert.Nil(t, attendant)
@@ -23,7 +25,7 @@ func TestUtility(t *testing.T) {
 	t.Run("should return error when given invalid capacity list SetupHandler arguments", func(t *testing.T) {
 		arg := "1,2,f"
 
-		attendant, err := parking.SetupHandler(arg)
+		attendant, err := parking.SetupHandler(arg, nil)
 
 		assert.ErrorIs(t, parking.ErrInvalidInput, err)
 		assert.Nil(t, attendant)
@@ -32,12 +34,46 @@ func TestUtility(t *testing.T) {
 	t.Run("should create new Attendant when given correct SetupHandler arguments", func(t *testing.T) {
 		arg := "1,2,3"
 
-		attendant, err := parking.SetupHandler(arg)
+		attendant, err := parking.SetupHandler(arg, nil)
 
 		assert.Nil(t, err)
 		assert.NotNil(t, attendant)
 	})
 
+	t.Run("should create named lots when given lot spec SetupHandler arguments", func(t *testing.T) {
+		attendant, err := parking.SetupHandler("A:1, B:2,3", nil)
+		ticket, _ := attendant.Park(&entity.Car{PlateNumber: "B 3 ST"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "A", ticket.Lot)
+		assert.Nil(t, attendant.CloseLot("B"))
+		assert.Nil(t, attendant.CloseLot("3"))
+	})
+
+	t.Run("should return error when given duplicate or empty lot ids or invalid capacities on SetupHandler", func(t *testing.T) {
+		_, err1 := parking.SetupHandler("A:1,A:2", nil)
+		_, err2 := parking.SetupHandler(":1", nil)
+		_, err3 := parking.SetupHandler("A:x", nil)
+		_, err4 := parking.SetupHandler("A:0", nil)
+		_, err5 := parking.SetupHandler("A:-1", nil)
+
+		assert.ErrorIs(t, err1, parking.ErrDuplicateLotID)
+		assert.ErrorIs(t, err2, parking.ErrInvalidInput)
+		assert.ErrorIs(t, err3, parking.ErrInvalidInput)
+		assert.ErrorIs(t, err4, parking.ErrInvalidCapacity)
+		assert.ErrorIs(t, err5, parking.ErrInvalidCapacity)
+	})
+
+	t.Run("should show lot id, name, location and tags on StatusHandler", func(t *testing.T) {
+		lot := parking.NewLot(1)
+		lot.SetInfo(parking.LotInfo{ID: "A", Name: "North", Location: "Level 2", Tags: []string{"ev", "covered"}})
+		attendant := parking.NewAttendant([]*parking.Lot{lot})
+
+		res, _ := parking.StatusHandler(attendant)
+
+		assert.Contains(t, res, "Lot #A North @ Level 2 [ev, covered]: 1 spaces left")
+	})
+
 	t.Run("should return error when given invalid ParkHandler arguments", func(t *testing.T) {
 		arg := ""
 		expected := ""
@@ -127,7 +163,7 @@ func TestUtility(t *testing.T) {
 		lot := parking.NewLot(1)
 		attendant := parking.NewAttendant([]*parking.Lot{lot})
 		car := &entity.Car{PlateNumber: "B 3 ST"}
-		expected := "Car B 3 ST succesfully unparked!"
+		expected := "Car B 3 ST succesfully unparked!\nDuration: 0h 00m\nAmount due: 0"
 		ticket, _ := lot.Park(car)
 
 		res, err := parking.UnParkHandler(ticket.ID, attendant)
@@ -150,11 +186,246 @@ func TestUtility(t *testing.T) {
 		attendant := parking.NewAttendant([]*parking.Lot{lot})
 		car := &entity.Car{PlateNumber: "B 3 ST"}
 		ticket, _ := lot.Park(car)
-		expected := fmt.Sprintf("Parking Lot Status:\nLot #1: 0 spaces left\n#%s %s\n", ticket.ID, car.PlateNumber)
+		expected := fmt.Sprintf("Parking Lot Status:\nParking style: first-available\nLot #1: 0 spaces left (motorcycle: 0, car: 0, van: 0, bus: 0)\n1-A [X]\n#%s %s @ 1-A-01\n", ticket.ID, car.PlateNumber)
 
 		res, err := parking.StatusHandler(attendant)
 
 		assert.Nil(t, err)
 		assert.Equal(t, expected, res)
 	})
+	t.Run("should return error when Attendant is not initialize on StyleListHandler", func(t *testing.T) {
+		res, err := parking.StyleListHandler(nil)
+
+		assert.ErrorIs(t, parking.ErrNoParkingLot, err)
+		assert.Equal(t, "", res)
+	})
+
+	t.Run("should list parking styles and mark current one on StyleListHandler", func(t *testing.T) {
+		attendant := parking.NewAttendant([]*parking.Lot{parking.NewLot(1)})
+		attendant.ChangeStyle(&parking.HighestCapacity{})
+		expected := "Parking styles:\n1. first-available\n2. highest-capacity (current)\n3. highest-free-space\n4. round-robin\n5. lowest-occupancy\n" +
+			"6. closest-to-entrance\n7. fill-smallest-first\n8. weighted-random"
+
+		res, err := parking.StyleListHandler(attendant)
+
+		assert.Nil(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("should return error when given invalid ChangeStyleHandler arguments", func(t *testing.T) {
+		attendant := parking.NewAttendant([]*parking.Lot{parking.NewLot(1)})
+
+		res, err := parking.ChangeStyleHandler("", attendant)
+
+		assert.ErrorIs(t, parking.ErrInvalidInput, err)
+		assert.Equal(t, "", res)
+	})
+
+	t.Run("should return error when given unknown style on ChangeStyleHandler", func(t *testing.T) {
+		attendant := parking.NewAttendant([]*parking.Lot{parking.NewLot(1)})
+
+		_, err1 := parking.ChangeStyleHandler("cheapest", attendant)
+		_, err2 := parking.ChangeStyleHandler("9", attendant)
+
+		assert.ErrorIs(t, err1, parking.ErrUnknownStyle)
+		assert.ErrorIs(t, err2, parking.ErrUnknownStyle)
+	})
+
+	t.Run("should change parking style by name or number on ChangeStyleHandler", func(t *testing.T) {
+		attendant := parking.NewAttendant([]*parking.Lot{parking.NewLot(1)})
+
+		res, err := parking.ChangeStyleHandler("highest-free-space", attendant)
+		_, _ = parking.ChangeStyleHandler("2", attendant)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "Parking style changed to highest-free-space", res)
+		assert.IsType(t, &parking.HighestCapacity{}, attendant.Style())
+	})
+	t.Run("should return error when given unknown vehicle type on ParkVehicleHandler", func(t *testing.T) {
+		attendant := parking.NewAttendant([]*parking.Lot{parking.NewLot(1)})
+
+		res, err := parking.ParkVehicleHandler("B 3 ST", "tank", attendant)
+
+		assert.ErrorIs(t, err, entity.ErrUnknownVehicleType)
+		assert.Equal(t, "", res)
+	})
+
+	t.Run("should park vehicle of given type on ParkVehicleHandler", func(t *testing.T) {
+		lot := parking.NewLot(3)
+		attendant := parking.NewAttendant([]*parking.Lot{lot})
+
+		res, err := parking.ParkVehicleHandler("B 3 ST", "van", attendant)
+
+		assert.Nil(t, err)
+		assert.Contains(t, res, "Car parked with ticket id")
+		assert.Equal(t, 1, lot.FreeSpace())
+	})
+
+	t.Run("should treat differently formatted plates as the same car on ParkHandler", func(t *testing.T) {
+		attendant := parking.NewAttendant([]*parking.Lot{parking.NewLot(2)})
+		_, _ = parking.ParkHandler("b 123 m", attendant)
+
+		res, err := parking.ParkHandler("B123M", attendant)
+		quote, quoteErr := parking.LostTicketQuoteHandler("B-123-M", attendant)
+
+		assert.ErrorIs(t, err, parking.ErrParkedCarTwice)
+		assert.Equal(t, "", res)
+		assert.Nil(t, quoteErr)
+		assert.Contains(t, quote, "Car B 123 M found at lot #1")
+	})
+
+	t.Run("should validate plate against attendant plate formats on ParkHandler", func(t *testing.T) {
+		attendant := parking.NewAttendant([]*parking.Lot{parking.NewLot(2)})
+		attendant.SetPlateFormats(plate.Indonesian)
+
+		res, err := parking.ParkHandler("P O LE", attendant)
+		_, validErr := parking.ParkHandler("b1234xyz", attendant)
+
+		assert.ErrorIs(t, err, plate.ErrInvalidPlate)
+		assert.Equal(t, "", res)
+		assert.Nil(t, validErr)
+		assert.True(t, attendant.IsCarParked(&entity.Car{PlateNumber: "B 1234 XYZ"}))
+	})
+
+	t.Run("should return error when given unknown permit on ParkPermitHandler", func(t *testing.T) {
+		attendant := parking.NewAttendant([]*parking.Lot{parking.NewLot(1)})
+
+		res, err := parking.ParkPermitHandler("B 3 ST", "", "gold", attendant)
+
+		assert.ErrorIs(t, err, entity.ErrUnknownPermit)
+		assert.Equal(t, "", res)
+	})
+
+	t.Run("should park permit holder by selection rule on ParkPermitHandler", func(t *testing.T) {
+		l1, l2 := parking.NewLot(2), parking.NewLot(2)
+		attendant := parking.NewAttendant([]*parking.Lot{l1, l2})
+		attendant.SetSelectionRules(parking.SelectionRule{Name: "vip", Match: parking.HasPermit(entity.PermitVIP), Style: &parking.RoundRobin{}})
+		_, _ = parking.ParkPermitHandler("B 1 VIP", "", "vip", attendant)
+
+		res, err := parking.ParkPermitHandler("B 2 VIP", "", "vip", attendant)
+
+		assert.Nil(t, err)
+		assert.Contains(t, res, "at lot #2")
+	})
+	t.Run("should return error when Attendant is not initialize on LostTicketQuoteHandler", func(t *testing.T) {
+		res, err := parking.LostTicketQuoteHandler("B 3 ST", nil)
+
+		assert.ErrorIs(t, parking.ErrNoParkingLot, err)
+		assert.Equal(t, "", res)
+	})
+
+	t.Run("should return penalty quote on LostTicketQuoteHandler", func(t *testing.T) {
+		attendant := parking.NewAttendant([]*parking.Lot{parking.NewLot(1)})
+		attendant.SetLostTicketPenalty(20000)
+		_, _ = parking.ParkHandler("B 3 ST", attendant)
+		expected := "Car B 3 ST found at lot #1 space 1-A-01\nDuration: 0h 00m\nLost ticket penalty: 20000\nAmount due: 20000"
+
+		res, err := parking.LostTicketQuoteHandler("B 3 ST", attendant)
+
+		assert.Nil(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("should not release car when lost ticket is not confirmed on LostTicketHandler", func(t *testing.T) {
+		attendant := parking.NewAttendant([]*parking.Lot{parking.NewLot(1)})
+		_, _ = parking.ParkHandler("B 3 ST", attendant)
+
+		res, err := parking.LostTicketHandler("B 3 ST", "n", attendant)
+
+		assert.ErrorIs(t, err, parking.ErrNotConfirmed)
+		assert.Equal(t, "", res)
+		events, _ := attendant.History(parking.AuditQuery{Action: parking.AuditLostTicket})
+		assert.Empty(t, events)
+	})
+
+	t.Run("should release car when lost ticket is confirmed on LostTicketHandler", func(t *testing.T) {
+		attendant := parking.NewAttendant([]*parking.Lot{parking.NewLot(1)})
+		attendant.SetLostTicketPenalty(20000)
+		_, _ = parking.ParkHandler("B 3 ST", attendant)
+		expected := "Car B 3 ST released without ticket!\nDuration: 0h 00m\nLost ticket penalty: 20000\nAmount due: 20000"
+
+		res, err := parking.LostTicketHandler("B 3 ST", "y", attendant)
+
+		assert.Nil(t, err)
+		assert.Equal(t, expected, res)
+	})
+	t.Run("should return error when given invalid ReserveHandler arguments", func(t *testing.T) {
+		attendant := parking.NewAttendant([]*parking.Lot{parking.NewLot(1)})
+
+		_, err1 := parking.ReserveHandler("1", "B 3 ST", "", "tomorrow", "2030-01-01 10:00", attendant)
+		_, err2 := parking.ReserveHandler("one", "B 3 ST", "", "2030-01-01 09:00", "2030-01-01 10:00", attendant)
+
+		assert.ErrorIs(t, err1, parking.ErrInvalidInput)
+		assert.ErrorIs(t, err2, parking.ErrUnknownLot)
+	})
+
+	t.Run("should return error when given invalid lot administration arguments", func(t *testing.T) {
+		attendant := parking.NewAttendant([]*parking.Lot{parking.NewLot(1)})
+
+		_, addErr := parking.AddLotHandler("0", attendant)
+		_, resizeErr := parking.ResizeLotHandler("1", "x", attendant)
+		_, closeErr := parking.CloseLotHandler("", attendant)
+		_, reopenErr := parking.ReopenLotHandler("1", nil)
+
+		assert.ErrorIs(t, addErr, parking.ErrInvalidCapacity)
+		assert.ErrorIs(t, resizeErr, parking.ErrInvalidInput)
+		assert.ErrorIs(t, closeErr, parking.ErrInvalidInput)
+		assert.ErrorIs(t, reopenErr, parking.ErrNoParkingLot)
+	})
+
+	t.Run("should add, resize, close and reopen lots through handlers", func(t *testing.T) {
+		attendant := parking.NewAttendant([]*parking.Lot{parking.NewLot(1)})
+
+		added, _ := parking.AddLotHandler("2", attendant)
+		resized, _ := parking.ResizeLotHandler("2", "3", attendant)
+		closed, _ := parking.CloseLotHandler("2", attendant)
+		status, _ := parking.StatusHandler(attendant)
+		reopened, _ := parking.ReopenLotHandler("2", attendant)
+
+		assert.Equal(t, "Lot #2 added with 2 spaces", added)
+		assert.Equal(t, "Lot #2 resized to 3 spaces", resized)
+		assert.Equal(t, "Lot #2 closed", closed)
+		assert.Contains(t, status, "Lot #2: 3 spaces left (motorcycle: 3, car: 3, van: 1, bus: 1) [closed]\n")
+		assert.Equal(t, "Lot #2 reopened", reopened)
+	})
+
+	t.Run("should return error when Attendant is not initialize on HistoryHandler", func(t *testing.T) {
+		res, err := parking.HistoryHandler("", "", "", nil)
+
+		assert.ErrorIs(t, err, parking.ErrNoParkingLot)
+		assert.Equal(t, "", res)
+	})
+
+	t.Run("should return error when given invalid time range on HistoryHandler", func(t *testing.T) {
+		attendant := parking.NewAttendant([]*parking.Lot{parking.NewLot(1)})
+
+		res, err := parking.HistoryHandler("", "yesterday", "", attendant)
+
+		assert.ErrorIs(t, err, parking.ErrInvalidInput)
+		assert.Equal(t, "", res)
+	})
+
+	t.Run("should list transactions of a plate on HistoryHandler", func(t *testing.T) {
+		attendant := parking.NewAttendant([]*parking.Lot{parking.NewLot(1)})
+		attendant.SetTicketIssuer(&fixedIssuer{id: "1000"})
+		attendant.SetClock(&fakeClock{now: time.Date(2023, 1, 2, 9, 0, 0, 0, time.Local)})
+		_, _ = parking.ParkHandler("B 3 ST", attendant)
+		_, _ = parking.ParkHandler("P O LE", attendant)
+		expected := "Parking History:\n" +
+			"2023-01-02 09:00 park #1000 B 3 ST"
+
+		res, err := parking.HistoryHandler("B 3 ST", "2023-01-02 08:00", "2023-01-02 10:00", attendant)
+
+		assert.Nil(t, err)
+		assert.Equal(t, expected, res)
+	})
+
+	t.Run("should reserve space on ReserveHandler", func(t *testing.T) {
+		attendant := parking.NewAttendant([]*parking.Lot{parking.NewLot(1)})
+
+		res, err := parking.ReserveHandler("1", "B 3 ST", "", "2030-01-01 09:00", "2030-01-01 10:00", attendant)
+
+		assert.Nil(t, err)
+		assert.Contains(t, res, "Space reserved for B 3 ST in lot #1 with reservation id R")
+	})
 }
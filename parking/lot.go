@@ -2,20 +2,58 @@ package parking
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
+	"sync"
 
 	"github.com/adityatresnobudi/parking-system/entity"
+	"github.com/adityatresnobudi/parking-system/plate"
 )
 
 var (
 	ErrUnrecognizedParkingTicket = errors.New("unrecognized parking ticket")
 	ErrUnavailablePosition       = errors.New("no available position")
 	ErrParkedCarTwice            = errors.New("car already inside")
+	ErrDuplicateTicketID         = errors.New("ticket id already issued")
+	ErrVehicleNotAccepted        = errors.New("vehicle type not accepted")
+	ErrCarNotFound               = errors.New("car not found")
+	ErrDuplicateLotID            = errors.New("lot id already in use")
 )
 
+var DefaultVehicleSlots = map[entity.VehicleType]int{
+	entity.VehicleMotorcycle: 1,
+	entity.VehicleCar:        1,
+	entity.VehicleVan:        2,
+	entity.VehicleBus:        3,
+}
+
 type Lot struct {
-	parkedCars  map[string]*entity.Car
-	subscribers []Subscriber
-	capacity    int
+	mu           sync.RWMutex
+	info         LotInfo
+	parkedCars   map[string]*entity.Car
+	plates       map[string]string
+	tickets      map[string]entity.Ticket
+	events       *SyncBus
+	capacity     int
+	usedSlots    int
+	spaces       []Space
+	spacesPerRow int
+	level        string
+	reserved     map[string]Reservation
+	reservations entity.TicketIssuer
+	slots        map[entity.VehicleType]int
+	issuer       entity.TicketIssuer
+	clock        Clock
+	full         bool
+	closed       bool
+}
+
+type LotInfo struct {
+	ID       string   `json:"id,omitempty"`
+	Name     string   `json:"name,omitempty"`
+	Location string   `json:"location,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Distance int      `json:"distance,omitempty"`
 }
 
 type Subscriber interface {
@@ -23,88 +61,419 @@ type Subscriber interface {
 	NotifyLotIsNotFull(*Lot)
 }
 
-type LotStatus struct {
-	freeSpace  int
-	parkedCars map[string]*entity.Car
+func NewLot(capacity int) *Lot {
+	return NewLotWithVehicles(capacity, DefaultVehicleSlots)
 }
 
-func NewLot(capacity int) *Lot {
+func NewLotWithVehicles(capacity int, vehicleSlots map[entity.VehicleType]int) *Lot {
+	return NewLotWithLayout(capacity, DefaultSpacesPerRow, "1", vehicleSlots)
+}
+
+// NewLotWithLayout numbers capacity spaces on level, spacesPerRow to a row.
+// Resize keeps numbering new spaces the same way.
+func NewLotWithLayout(capacity int, spacesPerRow int, level string, vehicleSlots map[entity.VehicleType]int) *Lot {
+	lot := NewLotWithSpaces(NumberedSpaces(capacity, spacesPerRow, level), vehicleSlots)
+	lot.spacesPerRow = spacesPerRow
+	lot.level = level
+	return lot
+}
+
+func NewLotWithSpaces(spaces []Space, vehicleSlots map[entity.VehicleType]int) *Lot {
+	layout := make([]Space, len(spaces))
+	copy(layout, spaces)
+	slots := make(map[entity.VehicleType]int, len(vehicleSlots))
+	for vt, n := range vehicleSlots {
+		slots[vt] = n
+	}
+	level := "1"
+	if len(layout) > 0 {
+		level = layout[len(layout)-1].Level
+	}
 	return &Lot{
-		parkedCars:  make(map[string]*entity.Car),
-		subscribers: make([]Subscriber, 0),
-		capacity:    capacity,
+		parkedCars:   make(map[string]*entity.Car),
+		plates:       make(map[string]string),
+		tickets:      make(map[string]entity.Ticket),
+		events:       NewSyncBus(),
+		capacity:     len(layout),
+		spaces:       layout,
+		spacesPerRow: DefaultSpacesPerRow,
+		level:        level,
+		reserved:     make(map[string]Reservation),
+		reservations: entity.NewSequentialIssuer(1),
+		slots:        slots,
+		issuer:       entity.DefaultTicketIssuer,
+		clock:        SystemClock{},
 	}
 }
 
 func (l *Lot) Park(car *entity.Car) (*entity.Ticket, error) {
-	if !l.IsNotFull() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	ticket, err := l.park(car)
+	if err != nil {
+		err = l.wrapErr(err)
+		l.events.Publish(ParkRejected{Lot: l, Car: car, Err: err})
+	}
+	return ticket, err
+}
+
+func (l *Lot) park(car *entity.Car) (*entity.Ticket, error) {
+	if l.closed {
+		return nil, ErrLotClosed
+	}
+	if !l.accepts(car.VehicleType()) {
+		return nil, ErrVehicleNotAccepted
+	}
+	first := l.findFreeSpaces(l.slots[car.VehicleType()])
+	if first == -1 || l.countFreeSpace() < l.slots[car.VehicleType()] {
 		return nil, ErrUnavailablePosition
 	}
-	if l.IsCarParked(car) {
+	if l.isCarParked(car) {
 		return nil, ErrParkedCarTwice
 	}
-	newTicket := entity.NewTicket()
-	l.parkedCars[newTicket.ID] = car
-	if !l.IsNotFull() {
-		l.notifySubscibersFull()
+	newTicket := l.issuer.Issue()
+	if _, ok := l.parkedCars[newTicket.ID]; ok {
+		return nil, ErrDuplicateTicketID
 	}
+	newTicket.EntryTime = l.clock.Now()
+	newTicket.Lot = l.info.ID
+	newTicket.Space = l.spaces[first].Label()
+	l.occupy(newTicket, car, first)
+	l.events.Publish(CarParked{Lot: l, Car: car, Ticket: newTicket})
+	l.publishFullness()
 	return &newTicket, nil
 }
 
 func (l *Lot) UnPark(ticket *entity.Ticket) (*entity.Car, error) {
-	if _, ok := l.parkedCars[ticket.ID]; !ok {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	unparkedCar, ok := l.parkedCars[ticket.ID]
+	if !ok {
 		return nil, ErrUnrecognizedParkingTicket
 	}
-	unparkedCar := l.parkedCars[ticket.ID]
-	delete(l.parkedCars, ticket.ID)
-	l.notifySubscibersNotFull()
+	issued := l.tickets[ticket.ID]
+	l.release(ticket.ID)
+	l.events.Publish(CarUnparked{Lot: l, Car: unparkedCar, Ticket: issued})
+	l.publishFullness()
 	return unparkedCar, nil
 }
 
-func (l *Lot) IsCarParked(car *entity.Car) bool {
-	for _, value := range l.parkedCars {
-		if value.PlateNumber == car.PlateNumber {
+func (l *Lot) ID() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.info.ID
+}
+
+func (l *Lot) Info() LotInfo {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.copyInfo()
+}
+
+func (l *Lot) copyInfo() LotInfo {
+	info := l.info
+	info.Tags = append([]string(nil), l.info.Tags...)
+	return info
+}
+
+func (l *Lot) SetInfo(info LotInfo) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.info = info
+	l.info.Tags = append([]string(nil), info.Tags...)
+}
+
+func (l *Lot) HasTag(tag string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for _, t := range l.info.Tags {
+		if t == tag {
 			return true
 		}
 	}
 	return false
 }
 
+func (l *Lot) setID(id string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.info.ID = id
+}
+
+func (l *Lot) wrapErr(err error) error {
+	if l.info.ID == "" {
+		return err
+	}
+	return fmt.Errorf("lot %s: %w", l.info.ID, err)
+}
+
+func (l *Lot) SetTicketIssuer(issuer entity.TicketIssuer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.issuer = issuer
+}
+
+func (l *Lot) SetClock(clock Clock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.clock = clock
+}
+
+func (l *Lot) ticket(id string) (entity.Ticket, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	ticket, ok := l.tickets[id]
+	return ticket, ok
+}
+
+func (l *Lot) parkedCar(id string) *entity.Car {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.parkedCars[id]
+}
+
+func (l *Lot) findPlate(plateNumber string) (string, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	id, ok := l.plates[plate.Normalize(plateNumber)]
+	return id, ok
+}
+
+func (l *Lot) IsCarParked(car *entity.Car) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.isCarParked(car)
+}
+
+func (l *Lot) isCarParked(car *entity.Car) bool {
+	_, ok := l.plates[plate.Normalize(car.PlateNumber)]
+	return ok
+}
+
 func (l *Lot) IsNotFull() bool {
-	return len(l.parkedCars) < l.capacity
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.isNotFull()
 }
 
-func (l *Lot) Subscribe(sub Subscriber) {
-	l.subscribers = append(l.subscribers, sub)
+func (l *Lot) isNotFull() bool {
+	return l.countFreeSpace() > 0
 }
 
-func (l *Lot) notifySubscibersFull() {
-	for _, sub := range l.subscribers {
-		sub.NotifyLotIsFull(l)
+func (l *Lot) Accepts(vt entity.VehicleType) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.accepts(vt)
+}
+
+func (l *Lot) accepts(vt entity.VehicleType) bool {
+	n, ok := l.slots[vt]
+	return ok && n > 0
+}
+
+func (l *Lot) CanFit(car *entity.Car) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.canFit(car)
+}
+
+func (l *Lot) canFit(car *entity.Car) bool {
+	n := l.slots[car.VehicleType()]
+	return !l.closed && l.accepts(car.VehicleType()) && l.findFreeSpaces(n) != -1 && l.countFreeSpace() >= n
+}
+
+func (l *Lot) findFreeSpaces(n int) int {
+	run := 0
+	for i, space := range l.spaces {
+		if !space.IsFree() || (run > 0 && !space.sameRow(l.spaces[i-1])) {
+			run = 0
+		}
+		if space.IsFree() {
+			run++
+		}
+		if run == n {
+			return i - n + 1
+		}
+	}
+	return -1
+}
+
+func (l *Lot) occupy(ticket entity.Ticket, car *entity.Car, first int) {
+	n := l.slots[car.VehicleType()]
+	for i := first; i < first+n; i++ {
+		l.spaces[i].TicketID = ticket.ID
 	}
+	l.parkedCars[ticket.ID] = car
+	l.plates[plate.Normalize(car.PlateNumber)] = ticket.ID
+	l.tickets[ticket.ID] = ticket
+	l.usedSlots += n
 }
 
-func (l *Lot) notifySubscibersNotFull() {
-	for _, sub := range l.subscribers {
-		sub.NotifyLotIsNotFull(l)
+func (l *Lot) release(ticketID string) {
+	for i := range l.spaces {
+		if l.spaces[i].TicketID == ticketID {
+			l.spaces[i].TicketID = ""
+			l.usedSlots--
+		}
+	}
+	if car, ok := l.parkedCars[ticketID]; ok {
+		delete(l.plates, plate.Normalize(car.PlateNumber))
+	}
+	delete(l.parkedCars, ticketID)
+	delete(l.tickets, ticketID)
+}
+
+func (l *Lot) spaceIdx(label string) int {
+	for i, space := range l.spaces {
+		if space.Label() == label {
+			return i
+		}
+	}
+	return -1
+}
+
+func (l *Lot) Subscribe(sub Subscriber) {
+	l.events.Subscribe(subscriberAdapter{sub: sub})
+}
+
+func (l *Lot) Events() EventBus {
+	return l.events
+}
+
+func (l *Lot) publishCapacityChanged() {
+	l.events.Publish(CapacityChanged{Lot: l, Capacity: l.capacity, FreeSpace: l.countFreeSpace()})
+	l.publishFullness()
+}
+
+func (l *Lot) publishFullness() {
+	full := !l.isAvailable()
+	if full == l.full {
+		return
+	}
+	l.full = full
+	if full {
+		l.events.Publish(LotFull{Lot: l})
+	} else {
+		l.events.Publish(LotAvailable{Lot: l})
 	}
 }
 
 func (l *Lot) HasMoreCapacity(lot *Lot) bool {
-	return l.capacity > lot.capacity
+	return l.Capacity() > lot.Capacity()
 }
 
 func (l *Lot) HasMoreFreeSpace(lot *Lot) bool {
-	return l.countFreeSpace() > lot.countFreeSpace()
+	return l.FreeSpace() > lot.FreeSpace()
+}
+
+func (l *Lot) View() LotView {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return LotView{
+		Info:       l.copyInfo(),
+		Capacity:   l.capacity,
+		FreeSpace:  l.countFreeSpace(),
+		FreeByType: l.countFreeByType(),
+		Closed:     l.closed,
+	}
+}
+
+func (l *Lot) Capacity() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.capacity
+}
+
+func (l *Lot) FreeSpace() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.countFreeSpace()
 }
 
 func (l *Lot) countFreeSpace() int {
-	return l.capacity - len(l.parkedCars)
+	free := l.capacity - l.usedSlots - l.reservedSlots(l.clock.Now())
+	if free < 0 {
+		return 0
+	}
+	return free
+}
+
+func (l *Lot) countFreeByType() map[entity.VehicleType]int {
+	output := make(map[entity.VehicleType]int, len(l.slots))
+	for vt, n := range l.slots {
+		if n > 0 {
+			output[vt] = l.countFits(n)
+			if free := l.countFreeSpace() / n; free < output[vt] {
+				output[vt] = free
+			}
+		}
+	}
+	return output
+}
+
+func (l *Lot) countFits(n int) int {
+	output, run := 0, 0
+	for i, space := range l.spaces {
+		if !space.IsFree() || (run > 0 && !space.sameRow(l.spaces[i-1])) {
+			output += run / n
+			run = 0
+		}
+		if space.IsFree() {
+			run++
+		}
+	}
+	return output + run/n
 }
 
 func (l *Lot) Status() LotStatus {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	parkedCars := make([]ParkedCar, 0, len(l.parkedCars))
+	for id, car := range l.parkedCars {
+		parkedCars = append(parkedCars, ParkedCar{Ticket: l.tickets[id], Car: *car, Space: spaceOf(l.spaces, id)})
+	}
+	SortParkedCars(parkedCars, OrderByTicket)
 	return LotStatus{
-		freeSpace:  l.countFreeSpace(),
-		parkedCars: l.parkedCars,
+		Info:       l.copyInfo(),
+		Capacity:   l.capacity,
+		FreeSpace:  l.countFreeSpace(),
+		FreeByType: l.countFreeByType(),
+		Closed:     l.closed,
+		ParkedCars: parkedCars,
+		Spaces:     append([]Space(nil), l.spaces...),
+		Reserved:   l.sortedReservations(),
+	}
+}
+
+func checkLotIDs(lots []*Lot) error {
+	used := make(map[string]bool, len(lots))
+	for _, l := range lots {
+		id := l.ID()
+		if id == "" {
+			continue
+		}
+		if used[id] {
+			return fmt.Errorf("%w: %s", ErrDuplicateLotID, id)
+		}
+		used[id] = true
+	}
+	return nil
+}
+
+func assignLotIDs(lots []*Lot) {
+	used := make(map[string]bool, len(lots))
+	for _, l := range lots {
+		used[l.ID()] = true
+	}
+	for i, l := range lots {
+		if l.ID() != "" {
+			continue
+		}
+		n := i + 1
+		for used[strconv.Itoa(n)] {
+			n++
+		}
+		l.setID(strconv.Itoa(n))
+		used[strconv.Itoa(n)] = true
 	}
 }
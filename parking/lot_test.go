@@ -2,6 +2,7 @@ package parking_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/adityatresnobudi/parking-system/entity"
 	"github.com/adityatresnobudi/parking-system/mocks"
@@ -34,6 +35,17 @@ func TestPark(t *testing.T) {
 		assert.ErrorIs(t, err2, parking.ErrParkedCarTwice)
 	})
 
+	t.Run("should return error if same plate enters twice with different formatting", func(t *testing.T) {
+		p := parking.NewLot(2)
+
+		_, _ = p.Park(&entity.Car{PlateNumber: "b 123 m"})
+		ticket, err := p.Park(&entity.Car{PlateNumber: "B123M"})
+
+		assert.Nil(t, ticket)
+		assert.ErrorIs(t, err, parking.ErrParkedCarTwice)
+		assert.True(t, p.IsCarParked(&entity.Car{PlateNumber: "B 123 M"}))
+	})
+
 	t.Run("should return error if there is no available position", func(t *testing.T) {
 		p := parking.NewLot(2)
 		car1 := &entity.Car{PlateNumber: "T 3 ST"}
@@ -65,6 +77,90 @@ func TestPark(t *testing.T) {
 	})
 }
 
+func TestLotTicketIssuer(t *testing.T) {
+
+	t.Run("should return error if issuer hands out an ID already in the lot", func(t *testing.T) {
+		p := parking.NewLot(2)
+		p.SetTicketIssuer(&fixedIssuer{id: "1000"})
+		car1 := &entity.Car{PlateNumber: "T 3 ST"}
+		car2 := &entity.Car{PlateNumber: "P O LE"}
+
+		_, _ = p.Park(car1)
+		ticket, err := p.Park(car2)
+
+		assert.Nil(t, ticket)
+		assert.ErrorIs(t, err, parking.ErrDuplicateTicketID)
+	})
+}
+
+type fixedIssuer struct {
+	id string
+}
+
+func (f *fixedIssuer) Issue() entity.Ticket {
+	return entity.Ticket{ID: f.id}
+}
+
+func TestLotEntryTime(t *testing.T) {
+
+	t.Run("should stamp ticket with entry time from lot clock", func(t *testing.T) {
+		p := parking.NewLot(1)
+		entry := time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC)
+		p.SetClock(&fakeClock{now: entry})
+
+		ticket, _ := p.Park(&entity.Car{PlateNumber: "T 3 ST"})
+
+		assert.Equal(t, entry, ticket.EntryTime)
+	})
+}
+
+func TestLotVehicleTypes(t *testing.T) {
+
+	t.Run("should return error if lot does not accept vehicle type", func(t *testing.T) {
+		p := parking.NewLotWithVehicles(5, map[entity.VehicleType]int{entity.VehicleCar: 1})
+		bus := &entity.Car{PlateNumber: "B 7 US", Type: entity.VehicleBus}
+
+		ticket, err := p.Park(bus)
+
+		assert.Nil(t, ticket)
+		assert.ErrorIs(t, err, parking.ErrVehicleNotAccepted)
+	})
+
+	t.Run("should consume slots according to vehicle type", func(t *testing.T) {
+		p := parking.NewLot(5)
+		van := &entity.Car{PlateNumber: "V 4 N", Type: entity.VehicleVan}
+		bus := &entity.Car{PlateNumber: "B 7 US", Type: entity.VehicleBus}
+
+		_, _ = p.Park(van)
+		_, _ = p.Park(bus)
+
+		assert.Equal(t, 0, p.FreeSpace())
+	})
+
+	t.Run("should return error if vehicle does not fit remaining slots", func(t *testing.T) {
+		p := parking.NewLot(2)
+		car := &entity.Car{PlateNumber: "T 3 ST"}
+		van := &entity.Car{PlateNumber: "V 4 N", Type: entity.VehicleVan}
+
+		_, _ = p.Park(car)
+		ticket, err := p.Park(van)
+
+		assert.Nil(t, ticket)
+		assert.ErrorIs(t, err, parking.ErrUnavailablePosition)
+		assert.True(t, p.IsNotFull())
+	})
+
+	t.Run("should release vehicle slots when unpark", func(t *testing.T) {
+		p := parking.NewLot(3)
+		bus := &entity.Car{PlateNumber: "B 7 US", Type: entity.VehicleBus}
+		ticket, _ := p.Park(bus)
+
+		_, _ = p.UnPark(ticket)
+
+		assert.Equal(t, 3, p.FreeSpace())
+	})
+}
+
 func TestUnpark(t *testing.T) {
 
 	t.Run("should return car when unpark if ticket exist", func(t *testing.T) {
@@ -122,11 +218,55 @@ func TestUnpark(t *testing.T) {
 		mockNotifySubs.On("NotifyLotIsNotFull", p).Return()
 
 		ticket, _ := a.Park(car)
-		returnedCar, _ := a.UnPark(ticket)
+		receipt, _ := a.UnPark(ticket)
 
 		assert.NotNil(t, ticket)
 		mockNotifySubs.AssertNumberOfCalls(t, "NotifyLotIsFull", 1)
-		assert.Same(t, expected, returnedCar)
+		assert.Same(t, expected, receipt.Car)
 		mockNotifySubs.AssertNumberOfCalls(t, "NotifyLotIsNotFull", 1)
 	})
 }
+
+func TestLotInfo(t *testing.T) {
+
+	t.Run("should stamp ticket with lot id", func(t *testing.T) {
+		p := parking.NewLot(1)
+		p.SetInfo(parking.LotInfo{ID: "A"})
+
+		ticket, _ := p.Park(&entity.Car{PlateNumber: "T 3 ST"})
+
+		assert.Equal(t, "A", ticket.Lot)
+	})
+
+	t.Run("should include lot id in park errors", func(t *testing.T) {
+		p := parking.NewLot(0)
+		p.SetInfo(parking.LotInfo{ID: "A"})
+
+		_, err := p.Park(&entity.Car{PlateNumber: "T 3 ST"})
+
+		assert.ErrorIs(t, err, parking.ErrUnavailablePosition)
+		assert.EqualError(t, err, "lot A: no available position")
+	})
+
+	t.Run("should assign numeric ids to unnamed lots without reusing taken ones", func(t *testing.T) {
+		l1, l2, l3 := parking.NewLot(1), parking.NewLot(1), parking.NewLot(1)
+		l1.SetInfo(parking.LotInfo{ID: "2"})
+
+		_ = parking.NewAttendant([]*parking.Lot{l1, l2, l3})
+
+		assert.Equal(t, "2", l1.ID())
+		assert.Equal(t, "3", l2.ID())
+		assert.Equal(t, "4", l3.ID())
+	})
+
+	t.Run("should return a copy of lot info", func(t *testing.T) {
+		p := parking.NewLot(1)
+		p.SetInfo(parking.LotInfo{ID: "A", Name: "North", Location: "Level 2", Tags: []string{"ev"}})
+
+		info := p.Info()
+		info.Tags[0] = "covered"
+
+		assert.True(t, p.HasTag("ev"))
+		assert.False(t, p.HasTag("covered"))
+	})
+}
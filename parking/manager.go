@@ -0,0 +1,111 @@
+package parking
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/adityatresnobudi/parking-system/entity"
+)
+
+var ErrAttendantNotInTeam = errors.New("attendant is not managed by this manager")
+
+type ParkingManager struct {
+	mu   sync.Mutex
+	self *Attendant
+	team []*Attendant
+}
+
+func NewParkingManager(lots []*Lot, team ...*Attendant) *ParkingManager {
+	return &ParkingManager{
+		self: NewAttendant(lots),
+		team: append([]*Attendant(nil), team...),
+	}
+}
+
+func (m *ParkingManager) AddAttendant(a *Attendant) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.teamIdx(a) == -1 {
+		m.team = append(m.team, a)
+	}
+}
+
+func (m *ParkingManager) Team() []*Attendant {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	output := make([]*Attendant, len(m.team))
+	copy(output, m.team)
+	return output
+}
+
+func (m *ParkingManager) ChangeStyle(style LotSelector) {
+	m.self.ChangeStyle(style)
+}
+
+func (m *ParkingManager) Park(car *entity.Car) (*entity.Ticket, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.isCarParked(car) {
+		return nil, ErrParkedCarTwice
+	}
+	members := append([]*Attendant{m.self}, m.team...)
+	for _, a := range members {
+		if a.CanPark(car) {
+			return a.Park(car)
+		}
+	}
+	for _, a := range members {
+		if a.Accepts(car.VehicleType()) {
+			return nil, ErrUnavailablePosition
+		}
+	}
+	return nil, ErrVehicleNotAccepted
+}
+
+func (m *ParkingManager) ParkBy(a *Attendant, car *entity.Car) (*entity.Ticket, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.teamIdx(a) == -1 {
+		return nil, ErrAttendantNotInTeam
+	}
+	if m.isCarParked(car) {
+		return nil, ErrParkedCarTwice
+	}
+	return a.Park(car)
+}
+
+func (m *ParkingManager) UnPark(ticket *entity.Ticket) (*Receipt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, a := range append([]*Attendant{m.self}, m.team...) {
+		if a.HasTicket(ticket) {
+			return a.UnPark(ticket)
+		}
+	}
+	return nil, ErrUnrecognizedParkingTicket
+}
+
+func (m *ParkingManager) Status() []LotStatus {
+	return m.self.Status()
+}
+
+func (m *ParkingManager) isCarParked(car *entity.Car) bool {
+	if m.self.IsCarParked(car) {
+		return true
+	}
+	for _, a := range m.team {
+		if a.IsCarParked(car) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *ParkingManager) teamIdx(a *Attendant) int {
+	for idx, member := range m.team {
+		if member == a {
+			return idx
+		}
+	}
+	return -1
+}
@@ -0,0 +1,107 @@
+package parking_test
+
+import (
+	"testing"
+
+	"github.com/adityatresnobudi/parking-system/entity"
+	"github.com/adityatresnobudi/parking-system/parking"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParkingManager(t *testing.T) {
+
+	t.Run("should park in own lots before delegating to the team", func(t *testing.T) {
+		own, teamLot := parking.NewLot(1), parking.NewLot(1)
+		m := parking.NewParkingManager([]*parking.Lot{own}, parking.NewAttendant([]*parking.Lot{teamLot}))
+
+		_, _ = m.Park(&entity.Car{PlateNumber: "T 3 ST"})
+
+		assert.Equal(t, 0, own.FreeSpace())
+		assert.Equal(t, 1, teamLot.FreeSpace())
+	})
+
+	t.Run("should delegate to an attendant when own lots are full", func(t *testing.T) {
+		teamLot := parking.NewLot(1)
+		a := parking.NewAttendant([]*parking.Lot{teamLot})
+		m := parking.NewParkingManager([]*parking.Lot{parking.NewLot(1)}, a)
+
+		_, _ = m.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		ticket, err := m.Park(&entity.Car{PlateNumber: "P O LE"})
+
+		assert.Nil(t, err)
+		assert.NotNil(t, ticket)
+		assert.Equal(t, 0, teamLot.FreeSpace())
+	})
+
+	t.Run("should return error when neither manager nor team has space", func(t *testing.T) {
+		m := parking.NewParkingManager([]*parking.Lot{parking.NewLot(1)}, parking.NewAttendant([]*parking.Lot{parking.NewLot(1)}))
+		_, _ = m.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		_, _ = m.Park(&entity.Car{PlateNumber: "P O LE"})
+
+		ticket, err := m.Park(&entity.Car{PlateNumber: "E 4 RR"})
+
+		assert.Nil(t, ticket)
+		assert.ErrorIs(t, err, parking.ErrUnavailablePosition)
+	})
+
+	t.Run("should return error when no lot accepts the vehicle type", func(t *testing.T) {
+		m := parking.NewParkingManager([]*parking.Lot{parking.NewLotWithVehicles(3, map[entity.VehicleType]int{entity.VehicleCar: 1})})
+
+		ticket, err := m.Park(&entity.Car{PlateNumber: "B 7 US", Type: entity.VehicleBus})
+
+		assert.Nil(t, ticket)
+		assert.ErrorIs(t, err, parking.ErrVehicleNotAccepted)
+	})
+
+	t.Run("should return error when the same car is parked twice across the team", func(t *testing.T) {
+		a := parking.NewAttendant([]*parking.Lot{parking.NewLot(1)})
+		m := parking.NewParkingManager([]*parking.Lot{parking.NewLot(1)}, a)
+		_, _ = m.ParkBy(a, &entity.Car{PlateNumber: "T 3 ST"})
+
+		ticket, err := m.Park(&entity.Car{PlateNumber: "T 3 ST"})
+
+		assert.Nil(t, ticket)
+		assert.ErrorIs(t, err, parking.ErrParkedCarTwice)
+	})
+
+	t.Run("should return error when delegating to attendant outside the team", func(t *testing.T) {
+		m := parking.NewParkingManager(nil)
+		outsider := parking.NewAttendant([]*parking.Lot{parking.NewLot(1)})
+
+		ticket, err := m.ParkBy(outsider, &entity.Car{PlateNumber: "T 3 ST"})
+
+		assert.Nil(t, ticket)
+		assert.ErrorIs(t, err, parking.ErrAttendantNotInTeam)
+	})
+
+	t.Run("should surface the error of the attendant it delegated to", func(t *testing.T) {
+		a := parking.NewAttendant([]*parking.Lot{parking.NewLotWithVehicles(1, map[entity.VehicleType]int{entity.VehicleCar: 1})})
+		m := parking.NewParkingManager(nil)
+		m.AddAttendant(a)
+
+		ticket, err := m.ParkBy(a, &entity.Car{PlateNumber: "V 4 N", Type: entity.VehicleVan})
+
+		assert.Nil(t, ticket)
+		assert.ErrorIs(t, err, parking.ErrVehicleNotAccepted)
+	})
+
+	t.Run("should redeem a delegated ticket through manager or the attendant that parked it", func(t *testing.T) {
+		a1 := parking.NewAttendant([]*parking.Lot{parking.NewLot(1)})
+		a2 := parking.NewAttendant([]*parking.Lot{parking.NewLot(1)})
+		m := parking.NewParkingManager(nil, a1, a2)
+		car1 := &entity.Car{PlateNumber: "T 3 ST"}
+		car2 := &entity.Car{PlateNumber: "P O LE"}
+		ticket1, _ := m.ParkBy(a2, car1)
+		ticket2, _ := m.Park(car2)
+
+		receipt1, err1 := m.UnPark(ticket1)
+		receipt2, err2 := a1.UnPark(ticket2)
+		_, err3 := m.UnPark(ticket1)
+
+		assert.Nil(t, err1)
+		assert.Same(t, car1, receipt1.Car)
+		assert.Nil(t, err2)
+		assert.Same(t, car2, receipt2.Car)
+		assert.ErrorIs(t, err3, parking.ErrUnrecognizedParkingTicket)
+	})
+}
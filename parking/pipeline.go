@@ -0,0 +1,216 @@
+package parking
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/adityatresnobudi/parking-system/entity"
+)
+
+var ErrInvalidPipeline = errors.New("invalid selection pipeline")
+
+// LotFilter reports whether a candidate lot may be selected at all.
+type LotFilter func(v LotView) bool
+
+// LotRanking compares two candidate lots and returns a negative number when a
+// should be preferred over b, a positive number when b should be preferred and
+// zero when the ranking cannot tell them apart.
+type LotRanking func(a LotView, b LotView) int
+
+// Pipeline is a LotSelector composed of filters followed by ordered rankings.
+// Each ranking only breaks the ties left by the rankings before it, and the
+// candidate ordering breaks any tie that is left after the last one.
+type Pipeline struct {
+	spec     string
+	filters  []LotFilter
+	rankings []LotRanking
+}
+
+// NewPipeline parses a comma separated list of steps, for example
+// "rank:tag=ev,rank:free-space,rank:id" for EV lots first, then the highest
+// free space, then the lowest lot ID. Steps are either a filter:
+//
+//	filter:open          lots that are not closed
+//	filter:accepts=TYPE  lots that accept the vehicle type
+//	filter:tag=TAG       lots with the tag
+//	filter:ev            lots with an EV charger, same as filter:tag=ev
+//
+// or a ranking, which may be prefixed with "-" to reverse it:
+//
+//	rank:tag=TAG     lots with the tag first
+//	rank:free-space  most free space first
+//	rank:capacity    highest capacity first
+//	rank:occupancy   lowest occupancy ratio first
+//	rank:distance    closest to the entrance first
+//	rank:id          lowest lot ID first
+func NewPipeline(spec string) (*Pipeline, error) {
+	p := &Pipeline{spec: spec}
+	for _, step := range strings.Split(spec, ",") {
+		step = strings.TrimSpace(step)
+		kind, rule, _ := strings.Cut(step, ":")
+		switch kind {
+		case "filter":
+			filter, err := parseFilter(rule)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: %v", ErrInvalidPipeline, step, err)
+			}
+			p.filters = append(p.filters, filter)
+		case "rank":
+			ranking, err := parseRanking(rule)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: %v", ErrInvalidPipeline, step, err)
+			}
+			p.rankings = append(p.rankings, ranking)
+		default:
+			return nil, fmt.Errorf("%w: %s: expected filter or rank step", ErrInvalidPipeline, step)
+		}
+	}
+	return p, nil
+}
+
+// Filter and Rank build a pipeline in code. They return the pipeline so that
+// steps can be chained.
+func (p *Pipeline) Filter(filters ...LotFilter) *Pipeline {
+	p.filters = append(p.filters, filters...)
+	return p
+}
+
+func (p *Pipeline) Rank(rankings ...LotRanking) *Pipeline {
+	p.rankings = append(p.rankings, rankings...)
+	return p
+}
+
+func (p *Pipeline) String() string {
+	if p.spec == "" {
+		return "custom"
+	}
+	return p.spec
+}
+
+func (p *Pipeline) SelectLot(availableLots []LotView) int {
+	selected := -1
+	for i, v := range availableLots {
+		if !p.accepts(v) {
+			continue
+		}
+		if selected == -1 || p.compare(v, availableLots[selected]) < 0 {
+			selected = i
+		}
+	}
+	return selected
+}
+
+func (p *Pipeline) accepts(v LotView) bool {
+	for _, filter := range p.filters {
+		if !filter(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Pipeline) compare(a LotView, b LotView) int {
+	for _, ranking := range p.rankings {
+		if c := ranking(a, b); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func IsOpen() LotFilter {
+	return func(v LotView) bool { return !v.Closed }
+}
+
+func AcceptsVehicle(vt entity.VehicleType) LotFilter {
+	return func(v LotView) bool { return v.Accepts(vt) }
+}
+
+func HasTag(tag string) LotFilter {
+	return func(v LotView) bool { return v.HasTag(tag) }
+}
+
+func TaggedFirst(tag string) LotRanking {
+	return func(a LotView, b LotView) int { return compareBool(a.HasTag(tag), b.HasTag(tag)) }
+}
+
+func MostFreeSpace(a LotView, b LotView) int {
+	return b.FreeSpace - a.FreeSpace
+}
+
+func HighestCapacityFirst(a LotView, b LotView) int {
+	return b.Capacity - a.Capacity
+}
+
+func LowestOccupancyFirst(a LotView, b LotView) int {
+	return compareBool(a.HasLowerOccupancy(b), b.HasLowerOccupancy(a))
+}
+
+func ClosestFirst(a LotView, b LotView) int {
+	return a.Info.Distance - b.Info.Distance
+}
+
+func LowestIDFirst(a LotView, b LotView) int {
+	return compareBool(lessID(a.Info.ID, b.Info.ID), lessID(b.Info.ID, a.Info.ID))
+}
+
+func Reverse(ranking LotRanking) LotRanking {
+	return func(a LotView, b LotView) int { return ranking(b, a) }
+}
+
+var rankings = map[string]LotRanking{
+	"free-space": MostFreeSpace,
+	"capacity":   HighestCapacityFirst,
+	"occupancy":  LowestOccupancyFirst,
+	"distance":   ClosestFirst,
+	"id":         LowestIDFirst,
+}
+
+func parseFilter(rule string) (LotFilter, error) {
+	name, arg, _ := strings.Cut(rule, "=")
+	switch {
+	case name == "open" && arg == "":
+		return IsOpen(), nil
+	case name == "ev" && arg == "":
+		return HasTag("ev"), nil
+	case name == "tag" && arg != "":
+		return HasTag(arg), nil
+	case name == "accepts" && arg != "":
+		vt, err := entity.ParseVehicleType(arg)
+		if err != nil {
+			return nil, err
+		}
+		return AcceptsVehicle(vt), nil
+	}
+	return nil, errors.New("unknown filter")
+}
+
+func parseRanking(rule string) (LotRanking, error) {
+	reverse := strings.HasPrefix(rule, "-")
+	name, arg, _ := strings.Cut(strings.TrimPrefix(rule, "-"), "=")
+	var ranking LotRanking
+	switch {
+	case name == "tag" && arg != "":
+		ranking = TaggedFirst(arg)
+	case arg == "" && rankings[name] != nil:
+		ranking = rankings[name]
+	default:
+		return nil, errors.New("unknown ranking")
+	}
+	if reverse {
+		return Reverse(ranking), nil
+	}
+	return ranking, nil
+}
+
+// compareBool prefers the side that is true.
+func compareBool(a bool, b bool) int {
+	switch {
+	case a && !b:
+		return -1
+	case b && !a:
+		return 1
+	}
+	return 0
+}
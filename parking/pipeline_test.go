@@ -0,0 +1,91 @@
+package parking_test
+
+import (
+	"testing"
+
+	"github.com/adityatresnobudi/parking-system/entity"
+	"github.com/adityatresnobudi/parking-system/parking"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipeline(t *testing.T) {
+	views := []parking.LotView{
+		{Info: parking.LotInfo{ID: "10"}, Capacity: 10, FreeSpace: 6, FreeByType: map[entity.VehicleType]int{entity.VehicleCar: 6}},
+		{Info: parking.LotInfo{ID: "2", Tags: []string{"ev"}}, Capacity: 4, FreeSpace: 2, FreeByType: map[entity.VehicleType]int{entity.VehicleCar: 2}},
+		{Info: parking.LotInfo{ID: "3", Tags: []string{"ev"}, Distance: 5}, Capacity: 8, FreeSpace: 6, FreeByType: map[entity.VehicleType]int{entity.VehicleCar: 6, entity.VehicleBus: 2}},
+		{Info: parking.LotInfo{ID: "1", Tags: []string{"ev"}}, Capacity: 6, FreeSpace: 6, FreeByType: map[entity.VehicleType]int{entity.VehicleCar: 6}, Closed: true},
+	}
+
+	tests := []struct {
+		name     string
+		spec     string
+		expected int
+	}{
+		{"should rank tagged lots first, then free space, then lowest id", "rank:tag=ev,rank:free-space,rank:id", 3},
+		{"should skip closed lots with the open filter", "filter:open,rank:tag=ev,rank:free-space,rank:id", 2},
+		{"should keep only lots with an ev charger", "filter:ev,rank:-free-space", 1},
+		{"should keep only lots accepting the vehicle type", "filter:accepts=bus", 2},
+		{"should break ties with candidate order after the last ranking", "rank:free-space", 0},
+		{"should reverse a ranking with a minus prefix", "rank:-capacity", 1},
+		{"should rank closest and lowest occupancy open lots first", "filter:open,rank:distance,rank:occupancy", 0},
+		{"should return -1 when every lot is filtered out", "filter:tag=covered", -1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := parking.NewPipeline(tc.spec)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, p.SelectLot(views))
+		})
+	}
+
+	t.Run("should return error for invalid pipeline steps", func(t *testing.T) {
+		for _, spec := range []string{"", "rank:nearest", "filter:accepts=truck", "sort:id", "filter:tag", "rank:id=1"} {
+			p, err := parking.NewPipeline(spec)
+
+			assert.Nil(t, p)
+			assert.ErrorIs(t, err, parking.ErrInvalidPipeline, spec)
+		}
+	})
+
+	t.Run("should compose a pipeline in code", func(t *testing.T) {
+		p := (&parking.Pipeline{}).Filter(parking.IsOpen()).Rank(parking.TaggedFirst("ev"), parking.LowestIDFirst)
+
+		assert.Equal(t, 1, p.SelectLot(views))
+	})
+
+	t.Run("should create pipeline style by spec and report it as style name", func(t *testing.T) {
+		style, err := parking.NewStyle("filter:open,rank:id")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "filter:open,rank:id", parking.StyleName(style))
+	})
+
+	t.Run("should park with pipeline style through attendant", func(t *testing.T) {
+		l1, l2, l3 := parking.NewLot(4), parking.NewLot(2), parking.NewLot(2)
+		l2.SetInfo(parking.LotInfo{Tags: []string{"ev"}})
+		l3.SetInfo(parking.LotInfo{Tags: []string{"ev"}})
+		a := parking.NewAttendant([]*parking.Lot{l1, l2, l3})
+		style, _ := parking.NewStyle("rank:tag=ev,rank:free-space,rank:id")
+		a.ChangeStyle(style)
+
+		lots := make([]string, 0)
+		for _, plate := range []string{"B 1 ST", "B 2 ST", "B 3 ST", "B 4 ST", "B 5 ST"} {
+			ticket, _ := a.Park(&entity.Car{PlateNumber: plate})
+			lots = append(lots, ticket.Lot)
+		}
+
+		assert.Equal(t, []string{"2", "3", "2", "3", "1"}, lots)
+	})
+
+	t.Run("should reject car when pipeline filters out every candidate", func(t *testing.T) {
+		a := parking.NewAttendant([]*parking.Lot{parking.NewLot(2)})
+		style, _ := parking.NewStyle("filter:ev")
+		a.ChangeStyle(style)
+
+		ticket, err := a.Park(&entity.Car{PlateNumber: "B 1 ST"})
+
+		assert.Nil(t, ticket)
+		assert.ErrorIs(t, err, parking.ErrUnavailablePosition)
+	})
+}
@@ -0,0 +1,91 @@
+package parking
+
+import (
+	"github.com/adityatresnobudi/parking-system/entity"
+	"github.com/adityatresnobudi/parking-system/plate"
+)
+
+// CarMatcher reports whether a selection rule applies to the car being parked.
+type CarMatcher func(car *entity.Car) bool
+
+// SelectionRule parks the cars it matches with its own Style instead of the
+// attendant's parking style. Rules are evaluated in order and the first match
+// wins.
+type SelectionRule struct {
+	Name  string
+	Match CarMatcher
+	Style LotSelector
+}
+
+func HasPermit(permit entity.Permit) CarMatcher {
+	return func(car *entity.Car) bool { return car.HasPermit(permit) }
+}
+
+func PlateIn(plates ...string) CarMatcher {
+	set := make(map[string]bool, len(plates))
+	for _, p := range plates {
+		set[plate.Normalize(p)] = true
+	}
+	return func(car *entity.Car) bool { return set[plate.Normalize(car.PlateNumber)] }
+}
+
+func VehicleIn(types ...entity.VehicleType) CarMatcher {
+	return func(car *entity.Car) bool {
+		for _, vt := range types {
+			if car.VehicleType() == vt {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Oversize matches vehicles that take more than one slot by default.
+func Oversize() CarMatcher {
+	return VehicleIn(entity.VehicleVan, entity.VehicleBus)
+}
+
+// AllOf matches cars that every matcher matches.
+func AllOf(matchers ...CarMatcher) CarMatcher {
+	return func(car *entity.Car) bool {
+		for _, m := range matchers {
+			if !m(car) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// AnyOf matches cars that at least one matcher matches.
+func AnyOf(matchers ...CarMatcher) CarMatcher {
+	return func(car *entity.Car) bool {
+		for _, m := range matchers {
+			if m(car) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func (a *Attendant) SetSelectionRules(rules ...SelectionRule) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.rules = append([]SelectionRule(nil), rules...)
+}
+
+func (a *Attendant) SelectionRules() []SelectionRule {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]SelectionRule(nil), a.rules...)
+}
+
+func (a *Attendant) styleFor(car *entity.Car) LotSelector {
+	for _, r := range a.rules {
+		if r.Match(car) {
+			return r.Style
+		}
+	}
+	return a.parkingStyle
+}
@@ -0,0 +1,80 @@
+package parking_test
+
+import (
+	"testing"
+
+	"github.com/adityatresnobudi/parking-system/entity"
+	"github.com/adityatresnobudi/parking-system/parking"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectionRules(t *testing.T) {
+	newAttendant := func() *parking.Attendant {
+		regular := parking.NewLot(4)
+		regular.SetInfo(parking.LotInfo{ID: "R", Distance: 30})
+		accessible := parking.NewLot(4)
+		accessible.SetInfo(parking.LotInfo{ID: "D", Distance: 20, Tags: []string{"accessible"}})
+		front := parking.NewLot(2)
+		front.SetInfo(parking.LotInfo{ID: "F", Distance: 10})
+		subscribers := parking.NewLot(3)
+		subscribers.SetInfo(parking.LotInfo{ID: "M", Distance: 40, Tags: []string{"monthly"}})
+		large := parking.NewLot(10)
+		large.SetInfo(parking.LotInfo{ID: "L", Distance: 50})
+
+		a := parking.NewAttendant([]*parking.Lot{regular, accessible, front, subscribers, large})
+		accessibleOnly, _ := parking.NewPipeline("filter:tag=accessible")
+		monthlyOnly, _ := parking.NewPipeline("filter:tag=monthly")
+		a.SetSelectionRules(
+			parking.SelectionRule{Name: "disabled", Match: parking.HasPermit(entity.PermitDisabled), Style: accessibleOnly},
+			parking.SelectionRule{Name: "vip", Match: parking.AnyOf(parking.HasPermit(entity.PermitVIP), parking.PlateIn("B 1 VIP")), Style: &parking.ClosestToEntrance{}},
+			parking.SelectionRule{Name: "monthly", Match: parking.HasPermit(entity.PermitMonthly), Style: monthlyOnly},
+			parking.SelectionRule{Name: "oversize", Match: parking.Oversize(), Style: &parking.HighestCapacity{}},
+		)
+		return a
+	}
+
+	tests := []struct {
+		name     string
+		car      *entity.Car
+		expected string
+	}{
+		{"should use default style when no rule matches", &entity.Car{PlateNumber: "T 3 ST"}, "R"},
+		{"should park disabled permit holder in accessible lot", &entity.Car{PlateNumber: "D 1 SBL", Permits: []entity.Permit{entity.PermitDisabled}}, "D"},
+		{"should park vip permit holder closest to the entrance", &entity.Car{PlateNumber: "V 1 P", Permits: []entity.Permit{entity.PermitVIP}}, "F"},
+		{"should park vip plate closest to the entrance", &entity.Car{PlateNumber: "B 1 VIP"}, "F"},
+		{"should park monthly subscriber in subscriber lot", &entity.Car{PlateNumber: "M 1 ON", Permits: []entity.Permit{entity.PermitMonthly}}, "M"},
+		{"should park oversize vehicle in highest capacity lot", &entity.Car{PlateNumber: "B 7 US", Type: entity.VehicleBus}, "L"},
+		{"should apply first matching rule", &entity.Car{PlateNumber: "B 1 VIP", Type: entity.VehicleVan, Permits: []entity.Permit{entity.PermitDisabled}}, "D"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			a := newAttendant()
+
+			ticket, err := a.Park(tc.car)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, ticket.Lot)
+		})
+	}
+
+	t.Run("should reject car when its rule leaves no candidate lot", func(t *testing.T) {
+		a := newAttendant()
+		for _, plate := range []string{"M 1", "M 2", "M 3"} {
+			_, _ = a.Park(&entity.Car{PlateNumber: plate, Permits: []entity.Permit{entity.PermitMonthly}})
+		}
+
+		ticket, err := a.Park(&entity.Car{PlateNumber: "M 4", Permits: []entity.Permit{entity.PermitMonthly}})
+
+		assert.Nil(t, ticket)
+		assert.ErrorIs(t, err, parking.ErrUnavailablePosition)
+	})
+
+	t.Run("should return a copy of the selection rules", func(t *testing.T) {
+		a := newAttendant()
+
+		rules := a.SelectionRules()
+		rules[0].Name = "changed"
+
+		assert.Equal(t, "disabled", a.SelectionRules()[0].Name)
+	})
+}
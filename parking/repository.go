@@ -0,0 +1,166 @@
+package parking
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/adityatresnobudi/parking-system/entity"
+	"github.com/adityatresnobudi/parking-system/plate"
+)
+
+var ErrNoSavedGarage = errors.New("no saved parking lot found")
+
+type Repository interface {
+	Load() (*GarageRecord, error)
+	SaveGarage(garage GarageRecord) error
+	SaveTicket(lotID string, record TicketRecord) error
+	DeleteTicket(ticketID string) error
+}
+
+type GarageRecord struct {
+	Lots []LotRecord `json:"lots"`
+}
+
+type LotRecord struct {
+	LotInfo
+	Capacity     int                        `json:"capacity"`
+	Vehicles     map[entity.VehicleType]int `json:"vehicles,omitempty"`
+	Spaces       []Space                    `json:"spaces,omitempty"`
+	SpacesPerRow int                        `json:"spaces_per_row,omitempty"`
+	Tickets      []TicketRecord             `json:"tickets"`
+	Reservations []Reservation              `json:"reservations,omitempty"`
+	Closed       bool                       `json:"closed,omitempty"`
+}
+
+type TicketRecord struct {
+	Ticket entity.Ticket `json:"ticket"`
+	Car    entity.Car    `json:"car"`
+}
+
+func RestoreAttendant(repo Repository) (*Attendant, error) {
+	garage, err := repo.Load()
+	if err != nil {
+		return nil, err
+	}
+	if garage == nil || len(garage.Lots) == 0 {
+		return nil, ErrNoSavedGarage
+	}
+
+	lots := make([]*Lot, 0, len(garage.Lots))
+	for _, lr := range garage.Lots {
+		vehicles := lr.Vehicles
+		if vehicles == nil {
+			vehicles = DefaultVehicleSlots
+		}
+		spaces := lr.Spaces
+		if len(spaces) == 0 {
+			spaces = NumberedSpaces(lr.Capacity, DefaultSpacesPerRow, "1")
+		}
+		lot := NewLotWithSpaces(spaces, vehicles)
+		if lr.SpacesPerRow > 0 {
+			lot.spacesPerRow = lr.SpacesPerRow
+		}
+		lot.SetInfo(lr.LotInfo)
+		lot.closed = lr.Closed
+		lot.full = !lot.isAvailable()
+		for _, tr := range lr.Tickets {
+			car := tr.Car
+			if err := lot.restore(tr.Ticket, &car); err != nil {
+				return nil, err
+			}
+		}
+		lot.restoreReservations(lr.Reservations)
+		lots = append(lots, lot)
+	}
+
+	a := NewAttendant(lots)
+	for i, lr := range garage.Lots {
+		for _, tr := range lr.Tickets {
+			lots[i].observeTicket(tr.Ticket.ID)
+		}
+	}
+	a.mu.Lock()
+	a.repo = repo
+	a.mu.Unlock()
+	return a, nil
+}
+
+func (a *Attendant) UseRepository(repo Repository) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := repo.SaveGarage(a.garageRecord()); err != nil {
+		return err
+	}
+	a.repo = repo
+	return nil
+}
+
+func (a *Attendant) garageRecord() GarageRecord {
+	garage := GarageRecord{Lots: make([]LotRecord, 0, len(a.lotList))}
+	for _, l := range a.lotList {
+		garage.Lots = append(garage.Lots, l.record())
+	}
+	return garage
+}
+
+func (l *Lot) observeTickets() {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if o, ok := l.issuer.(interface{ Observe(string) }); ok {
+		for id := range l.tickets {
+			o.Observe(id)
+		}
+	}
+}
+
+func (l *Lot) observeTicket(id string) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if o, ok := l.issuer.(interface{ Observe(string) }); ok {
+		o.Observe(id)
+	}
+}
+
+func (l *Lot) restore(ticket entity.Ticket, car *entity.Car) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	first := l.spaceIdx(ticket.Space)
+	if first == -1 {
+		first = l.findFreeSpaces(l.slots[car.VehicleType()])
+	}
+	if first == -1 {
+		return ErrUnavailablePosition
+	}
+	l.occupy(ticket, car, first)
+	l.full = !l.isAvailable()
+	return nil
+}
+
+// restoreReservations skips reservations whose car is already parked, which
+// happens when the reserved park was saved but the garage record was not.
+func (l *Lot) restoreReservations(reservations []Reservation) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, r := range reservations {
+		if o, ok := l.reservations.(interface{ Observe(string) }); ok {
+			o.Observe(strings.TrimPrefix(r.ID, l.reservationPrefix()))
+		}
+		if _, parked := l.plates[plate.Normalize(r.PlateNumber)]; !parked {
+			l.reserved[r.ID] = r
+		}
+	}
+}
+
+func (l *Lot) record() LotRecord {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	spaces := make([]Space, len(l.spaces))
+	for i, space := range l.spaces {
+		spaces[i] = Space{Level: space.Level, Row: space.Row, Number: space.Number}
+	}
+	output := LotRecord{LotInfo: l.copyInfo(), Capacity: l.capacity, Vehicles: l.slots, Spaces: spaces, SpacesPerRow: l.spacesPerRow, Tickets: make([]TicketRecord, 0, len(l.tickets)), Reservations: l.sortedReservations(), Closed: l.closed}
+	for id, ticket := range l.tickets {
+		output.Tickets = append(output.Tickets, TicketRecord{Ticket: ticket, Car: *l.parkedCars[id]})
+	}
+	return output
+}
@@ -0,0 +1,208 @@
+package parking_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adityatresnobudi/parking-system/entity"
+	"github.com/adityatresnobudi/parking-system/parking"
+	"github.com/stretchr/testify/assert"
+)
+
+var errRepositoryDown = errors.New("repository down")
+
+type memoryRepository struct {
+	garage  *parking.GarageRecord
+	failing bool
+}
+
+func (m *memoryRepository) Load() (*parking.GarageRecord, error) {
+	return m.garage, nil
+}
+
+func (m *memoryRepository) SaveGarage(garage parking.GarageRecord) error {
+	if m.failing {
+		return errRepositoryDown
+	}
+	m.garage = &garage
+	return nil
+}
+
+func (m *memoryRepository) SaveTicket(lotID string, record parking.TicketRecord) error {
+	if m.failing {
+		return errRepositoryDown
+	}
+	for i, lot := range m.garage.Lots {
+		if lot.ID == lotID {
+			m.garage.Lots[i].Tickets = append(lot.Tickets, record)
+		}
+	}
+	return nil
+}
+
+func (m *memoryRepository) DeleteTicket(ticketID string) error {
+	if m.failing {
+		return errRepositoryDown
+	}
+	for i, lot := range m.garage.Lots {
+		for j, tr := range lot.Tickets {
+			if tr.Ticket.ID == ticketID {
+				m.garage.Lots[i].Tickets = append(lot.Tickets[:j], lot.Tickets[j+1:]...)
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+func TestRepository(t *testing.T) {
+
+	t.Run("should return error when restoring from empty repository", func(t *testing.T) {
+		repo := &memoryRepository{}
+
+		attendant, err := parking.RestoreAttendant(repo)
+
+		assert.Nil(t, attendant)
+		assert.ErrorIs(t, err, parking.ErrNoSavedGarage)
+	})
+
+	t.Run("should record ticket in the lot it was parked in", func(t *testing.T) {
+		repo := &memoryRepository{}
+		attendant, _ := parking.SetupHandler("1,1", repo)
+
+		_, _ = attendant.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		_, _ = attendant.Park(&entity.Car{PlateNumber: "P O LE"})
+
+		assert.Equal(t, "T 3 ST", repo.garage.Lots[0].Tickets[0].Car.PlateNumber)
+		assert.Equal(t, "P O LE", repo.garage.Lots[1].Tickets[0].Car.PlateNumber)
+	})
+
+	t.Run("should record ticket by lot id", func(t *testing.T) {
+		repo := &memoryRepository{}
+		attendant, _ := parking.SetupHandler("B:1,A:1", repo)
+
+		_, _ = attendant.Park(&entity.Car{PlateNumber: "T 3 ST"})
+
+		assert.Equal(t, "B", repo.garage.Lots[0].ID)
+		assert.Equal(t, "T 3 ST", repo.garage.Lots[0].Tickets[0].Car.PlateNumber)
+		assert.Empty(t, repo.garage.Lots[1].Tickets)
+	})
+
+	t.Run("should not park car when repository fails to record ticket", func(t *testing.T) {
+		repo := &memoryRepository{}
+		attendant, _ := parking.SetupHandler("1", repo)
+		repo.failing = true
+
+		ticket, err := attendant.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		repo.failing = false
+		ticket2, err2 := attendant.Park(&entity.Car{PlateNumber: "T 3 ST"})
+
+		assert.Nil(t, ticket)
+		assert.ErrorIs(t, err, errRepositoryDown)
+		assert.NotNil(t, ticket2)
+		assert.Nil(t, err2)
+	})
+
+	t.Run("should keep car parked when repository fails to delete ticket", func(t *testing.T) {
+		repo := &memoryRepository{}
+		attendant, _ := parking.SetupHandler("1", repo)
+		ticket, _ := attendant.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		repo.failing = true
+
+		receipt, err := attendant.UnPark(ticket)
+
+		assert.Nil(t, receipt)
+		assert.ErrorIs(t, err, errRepositoryDown)
+	})
+
+	t.Run("should keep full restored lots out of available lots", func(t *testing.T) {
+		repo := &memoryRepository{}
+		attendant, _ := parking.SetupHandler("1,1", repo)
+		_, _ = attendant.Park(&entity.Car{PlateNumber: "T 3 ST"})
+
+		restored, _ := parking.RestoreAttendant(repo)
+
+		assert.Len(t, restored.GetAvailLots(), 1)
+	})
+
+	t.Run("should not reissue restored ticket IDs", func(t *testing.T) {
+		repo := &memoryRepository{}
+		attendant, _ := parking.SetupHandler("2", repo)
+		ticket1, _ := attendant.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		entity.DefaultTicketIssuer = entity.NewSequentialIssuer(1000)
+
+		restored, _ := parking.RestoreAttendant(repo)
+		ticket2, err := restored.Park(&entity.Car{PlateNumber: "P O LE"})
+
+		assert.Nil(t, err)
+		assert.NotEqual(t, ticket1.ID, ticket2.ID)
+	})
+	t.Run("should make full restored lot available again after unpark", func(t *testing.T) {
+		repo := &memoryRepository{}
+		attendant, _ := parking.SetupHandler("1", repo)
+		ticket, _ := attendant.Park(&entity.Car{PlateNumber: "T 3 ST"})
+
+		restored, _ := parking.RestoreAttendant(repo)
+		_, _ = restored.UnPark(ticket)
+
+		assert.Len(t, restored.GetAvailLots(), 1)
+	})
+	t.Run("should restore reservations without reissuing their ids", func(t *testing.T) {
+		repo := &memoryRepository{}
+		attendant, _ := parking.SetupHandler("1,1", repo)
+		start := time.Now().Add(time.Hour)
+		r1, _ := attendant.Reserve("1", &entity.Car{PlateNumber: "T 3 ST"}, start, start.Add(time.Hour))
+
+		restored, err := parking.RestoreAttendant(repo)
+		r2, _ := restored.Reserve("2", &entity.Car{PlateNumber: "P O LE"}, start, start.Add(time.Hour))
+
+		assert.Nil(t, err)
+		assert.Equal(t, []parking.Reservation{*r1, *r2}, restored.Reservations())
+		assert.NotEqual(t, r1.ID, r2.ID)
+	})
+	t.Run("should keep reservation when repository fails to record reserved park", func(t *testing.T) {
+		repo := &memoryRepository{}
+		attendant, _ := parking.SetupHandler("1", repo)
+		car := &entity.Car{PlateNumber: "T 3 ST"}
+		start := time.Now().Add(-time.Minute)
+		r, _ := attendant.Reserve("1", car, start, start.Add(time.Hour))
+		repo.failing = true
+
+		ticket, err := attendant.Park(car)
+
+		assert.Nil(t, ticket)
+		assert.ErrorIs(t, err, errRepositoryDown)
+		assert.False(t, attendant.IsCarParked(car))
+		assert.Equal(t, []parking.Reservation{*r}, attendant.Reservations())
+	})
+	t.Run("should not record an exit when repository fails to delete ticket", func(t *testing.T) {
+		repo := &memoryRepository{}
+		attendant, _ := parking.SetupHandler("1", repo)
+		ticket, _ := attendant.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		repo.failing = true
+
+		_, err := attendant.UnPark(ticket)
+		exits, _ := attendant.History(parking.AuditQuery{Action: parking.AuditUnPark})
+		failures, _ := attendant.History(parking.AuditQuery{Action: parking.AuditUnParkFailed})
+
+		assert.ErrorIs(t, err, errRepositoryDown)
+		assert.Empty(t, exits)
+		assert.Len(t, failures, 1)
+	})
+
+	t.Run("should keep saved ticket when checkout cannot be recorded", func(t *testing.T) {
+		repo := &memoryRepository{}
+		attendant, _ := parking.SetupHandler("1", repo)
+		trail := &failingAuditTrail{MemoryAuditTrail: parking.NewMemoryAuditTrail()}
+		attendant.SetAuditTrail(trail)
+		ticket, _ := attendant.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		trail.err = errors.New("disk full")
+
+		_, err := attendant.UnPark(ticket)
+
+		assert.EqualError(t, err, "disk full")
+		assert.Len(t, repo.garage.Lots[0].Tickets, 1)
+		assert.Equal(t, ticket.ID, repo.garage.Lots[0].Tickets[0].Ticket.ID)
+	})
+}
@@ -0,0 +1,253 @@
+package parking
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/adityatresnobudi/parking-system/entity"
+	"github.com/adityatresnobudi/parking-system/plate"
+)
+
+var (
+	ErrInvalidReservationWindow = errors.New("reservation must end after it starts")
+	ErrAlreadyReserved          = errors.New("plate already has a reservation")
+	ErrUnknownLot               = errors.New("unknown parking lot")
+	ErrDuplicateReservationID   = errors.New("reservation id already issued")
+)
+
+const AuditNoShow = "no_show"
+
+type Reservation struct {
+	ID          string             `json:"id"`
+	PlateNumber string             `json:"plate_number"`
+	VehicleType entity.VehicleType `json:"vehicle_type"`
+	Start       time.Time          `json:"start"`
+	End         time.Time          `json:"end"`
+}
+
+func (r Reservation) isActive(now time.Time) bool {
+	return !now.Before(r.Start) && now.Before(r.End)
+}
+
+func (r Reservation) isExpired(now time.Time) bool {
+	return !now.Before(r.End)
+}
+
+func (a *Attendant) Reserve(lotID string, car *entity.Car, start, end time.Time) (*Reservation, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	lot, err := findLot(a.lotList, lotID)
+	if err != nil {
+		return nil, err
+	}
+	if !end.After(start) || !end.After(a.clock.Now()) {
+		return nil, ErrInvalidReservationWindow
+	}
+	if a.isCarParked(car) {
+		return nil, ErrParkedCarTwice
+	}
+	a.expireReservations()
+	if lot, _ := a.findReservation(car.PlateNumber); lot != nil {
+		return nil, ErrAlreadyReserved
+	}
+
+	reservation, err := lot.reserve(Reservation{
+		PlateNumber: car.PlateNumber,
+		VehicleType: car.VehicleType(),
+		Start:       start,
+		End:         end,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := a.saveGarage(); err != nil {
+		lot.cancelReservation(reservation.ID)
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+func (a *Attendant) CancelReservation(id string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, lot := range a.lotList {
+		if lot.cancelReservation(id) {
+			return a.saveGarage()
+		}
+	}
+	return ErrUnrecognizedParkingTicket
+}
+
+func (a *Attendant) ExpireReservations() []Reservation {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.expireReservations()
+}
+
+func (a *Attendant) expireReservations() []Reservation {
+	output := make([]Reservation, 0)
+	now := a.clock.Now()
+	for _, lot := range a.lotList {
+		for _, r := range lot.expireReservations(now) {
+			_ = a.audit.Record(AuditEvent{
+				Time:        now,
+				Action:      AuditNoShow,
+				PlateNumber: r.PlateNumber,
+				TicketID:    r.ID,
+			})
+			output = append(output, r)
+		}
+	}
+	if len(output) > 0 {
+		_ = a.saveGarage()
+	}
+	return output
+}
+
+func (a *Attendant) findReservation(plateNumber string) (*Lot, Reservation) {
+	for _, lot := range a.lotList {
+		if r, ok := lot.reservationFor(plateNumber); ok {
+			return lot, r
+		}
+	}
+	return nil, Reservation{}
+}
+
+func (a *Attendant) Reservations() []Reservation {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	output := make([]Reservation, 0)
+	for _, lot := range a.lotList {
+		output = append(output, lot.Reservations()...)
+	}
+	return output
+}
+
+func (l *Lot) Reservations() []Reservation {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.sortedReservations()
+}
+
+func (l *Lot) sortedReservations() []Reservation {
+	output := make([]Reservation, 0, len(l.reserved))
+	for _, r := range l.reserved {
+		output = append(output, r)
+	}
+	sort.Slice(output, func(i int, j int) bool {
+		return output[i].Start.Before(output[j].Start)
+	})
+	return output
+}
+
+func (l *Lot) ParkReserved(car *entity.Car, reservationID string) (*entity.Ticket, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	r, ok := l.reserved[reservationID]
+	if !ok || !plate.Equal(r.PlateNumber, car.PlateNumber) {
+		return nil, ErrUnrecognizedParkingTicket
+	}
+	delete(l.reserved, reservationID)
+	ticket, err := l.park(car)
+	if err != nil {
+		l.reserved[reservationID] = r
+		l.events.Publish(ParkRejected{Lot: l, Car: car, Err: err})
+		return nil, err
+	}
+	return ticket, nil
+}
+
+// reserve holds space for r. A reservation without an ID gets the next one of
+// the lot, so attendants sharing the lot never hand out the same ID.
+func (l *Lot) reserve(r Reservation) (Reservation, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return Reservation{}, l.wrapErr(ErrLotClosed)
+	}
+	if !l.accepts(r.VehicleType) {
+		return Reservation{}, l.wrapErr(ErrVehicleNotAccepted)
+	}
+	if r.ID == "" {
+		r.ID = l.reservationPrefix() + l.reservations.Issue().ID
+	}
+	if _, ok := l.reserved[r.ID]; ok {
+		return Reservation{}, l.wrapErr(ErrDuplicateReservationID)
+	}
+	l.reserved[r.ID] = r
+	if l.usedSlots+l.reservedSlots(l.clock.Now()) > l.capacity {
+		delete(l.reserved, r.ID)
+		return Reservation{}, l.wrapErr(ErrUnavailablePosition)
+	}
+	l.publishCapacityChanged()
+	return r, nil
+}
+
+func (l *Lot) reservationPrefix() string {
+	return "R" + l.info.ID + "-"
+}
+
+func (l *Lot) cancelReservation(id string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.reserved[id]; !ok {
+		return false
+	}
+	delete(l.reserved, id)
+	l.publishCapacityChanged()
+	return true
+}
+
+func (l *Lot) expireReservations(now time.Time) []Reservation {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	output := make([]Reservation, 0)
+	for id, r := range l.reserved {
+		if r.isExpired(now) {
+			output = append(output, r)
+			delete(l.reserved, id)
+		}
+	}
+	if len(output) > 0 {
+		l.publishCapacityChanged()
+	}
+	return output
+}
+
+func (l *Lot) reservationFor(plateNumber string) (Reservation, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for _, r := range l.reserved {
+		if plate.Equal(r.PlateNumber, plateNumber) {
+			return r, true
+		}
+	}
+	return Reservation{}, false
+}
+
+// reservedSlots is the most slots the reservations hold at once from now on.
+// Walk-ins leave that many free, so a car parked before a window opens cannot
+// take the space promised to the reservation.
+func (l *Lot) reservedSlots(now time.Time) int {
+	output := 0
+	for _, r := range l.reserved {
+		if r.isExpired(now) {
+			continue
+		}
+		at := r.Start
+		if at.Before(now) {
+			at = now
+		}
+		held := 0
+		for _, other := range l.reserved {
+			if other.isActive(at) {
+				held += l.slots[other.VehicleType]
+			}
+		}
+		if held > output {
+			output = held
+		}
+	}
+	return output
+}
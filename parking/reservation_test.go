@@ -0,0 +1,198 @@
+package parking_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adityatresnobudi/parking-system/entity"
+	"github.com/adityatresnobudi/parking-system/parking"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReservation(t *testing.T) {
+	nine := time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC)
+
+	newAttendant := func(capacities ...int) (*parking.Attendant, []*parking.Lot, *fakeClock) {
+		lots := make([]*parking.Lot, 0)
+		for _, c := range capacities {
+			lots = append(lots, parking.NewLot(c))
+		}
+		a := parking.NewAttendant(lots)
+		clock := &fakeClock{now: nine}
+		a.SetClock(clock)
+		return a, lots, clock
+	}
+
+	t.Run("should issue unique reservation ids when two attendants share a lot", func(t *testing.T) {
+		a1, lots, clock := newAttendant(2)
+		a2 := parking.NewAttendant(lots)
+		a2.SetClock(clock)
+
+		r1, err1 := a1.Reserve("1", &entity.Car{PlateNumber: "T 3 ST"}, nine, nine.Add(time.Hour))
+		r2, err2 := a2.Reserve("1", &entity.Car{PlateNumber: "P O LE"}, nine, nine.Add(time.Hour))
+
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+		assert.Equal(t, "R1-1", r1.ID)
+		assert.Equal(t, "R1-2", r2.ID)
+		assert.Len(t, lots[0].Reservations(), 2)
+	})
+
+	t.Run("should return error when reserving unknown lot", func(t *testing.T) {
+		a, _, _ := newAttendant(1)
+
+		r, err := a.Reserve("2", &entity.Car{PlateNumber: "T 3 ST"}, nine, nine.Add(time.Hour))
+
+		assert.Nil(t, r)
+		assert.ErrorIs(t, err, parking.ErrUnknownLot)
+	})
+
+	t.Run("should return error when reservation window ends before it starts", func(t *testing.T) {
+		a, _, _ := newAttendant(1)
+
+		r, err := a.Reserve("1", &entity.Car{PlateNumber: "T 3 ST"}, nine, nine.Add(-time.Hour))
+
+		assert.Nil(t, r)
+		assert.ErrorIs(t, err, parking.ErrInvalidReservationWindow)
+	})
+
+	t.Run("should return error when plate reserves twice", func(t *testing.T) {
+		a, _, _ := newAttendant(2)
+		car := &entity.Car{PlateNumber: "T 3 ST"}
+
+		_, _ = a.Reserve("1", car, nine, nine.Add(time.Hour))
+		r, err := a.Reserve("1", car, nine, nine.Add(time.Hour))
+
+		assert.Nil(t, r)
+		assert.ErrorIs(t, err, parking.ErrAlreadyReserved)
+	})
+
+	t.Run("should return error when overlapping reservations exceed capacity", func(t *testing.T) {
+		a, _, _ := newAttendant(1)
+
+		_, _ = a.Reserve("1", &entity.Car{PlateNumber: "T 3 ST"}, nine, nine.Add(time.Hour))
+		r, err := a.Reserve("1", &entity.Car{PlateNumber: "P O LE"}, nine.Add(30*time.Minute), nine.Add(2*time.Hour))
+
+		assert.Nil(t, r)
+		assert.ErrorIs(t, err, parking.ErrUnavailablePosition)
+	})
+
+	t.Run("should hold space for walk-ins until reservation window ends", func(t *testing.T) {
+		a, lots, clock := newAttendant(2)
+
+		_, _ = a.Reserve("1", &entity.Car{PlateNumber: "T 3 ST"}, nine.Add(time.Hour), nine.Add(2*time.Hour))
+		before := lots[0].FreeSpace()
+		clock.now = nine.Add(time.Hour)
+		during := lots[0].FreeSpace()
+		clock.now = nine.Add(2 * time.Hour)
+
+		assert.Equal(t, 1, before)
+		assert.Equal(t, 1, during)
+		assert.Equal(t, 2, lots[0].FreeSpace())
+	})
+
+	t.Run("should reject walk-in before window that would take the reserved space", func(t *testing.T) {
+		a, lots, clock := newAttendant(1)
+		car := &entity.Car{PlateNumber: "T 3 ST"}
+
+		_, _ = a.Reserve("1", car, nine.Add(time.Hour), nine.Add(2*time.Hour))
+		walkIn, walkInErr := a.Park(&entity.Car{PlateNumber: "P O LE"})
+		clock.now = nine.Add(time.Hour + time.Minute)
+		free := lots[0].FreeSpace()
+		ticket, err := a.Park(car)
+
+		assert.Nil(t, walkIn)
+		assert.ErrorIs(t, walkInErr, parking.ErrUnavailablePosition)
+		assert.Equal(t, 0, free)
+		assert.Nil(t, err)
+		assert.Equal(t, "1", ticket.Lot)
+		assert.Equal(t, 0, lots[0].FreeSpace())
+	})
+
+	t.Run("should reject walk-in when remaining space is reserved", func(t *testing.T) {
+		a, lots, _ := newAttendant(1)
+
+		_, _ = a.Reserve("1", &entity.Car{PlateNumber: "T 3 ST"}, nine, nine.Add(time.Hour))
+		ticket, err := a.Park(&entity.Car{PlateNumber: "P O LE"})
+
+		assert.Nil(t, ticket)
+		assert.ErrorIs(t, err, parking.ErrUnavailablePosition)
+		assert.False(t, lots[0].IsNotFull())
+	})
+
+	t.Run("should park reserved plate in another lot when it arrives as a vehicle the reserved lot refuses", func(t *testing.T) {
+		a, lots, _ := newAttendant(1, 3)
+		_, _ = a.Reserve("1", &entity.Car{PlateNumber: "T 3 ST", Type: entity.VehicleMotorcycle}, nine, nine.Add(time.Hour))
+
+		ticket, err := a.Park(&entity.Car{PlateNumber: "T 3 ST", Type: entity.VehicleBus})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "2", ticket.Lot)
+		assert.Empty(t, a.Reservations())
+		assert.Equal(t, 1, lots[0].FreeSpace())
+	})
+
+	t.Run("should keep reservation when no lot takes the vehicle it arrives as", func(t *testing.T) {
+		a, _, _ := newAttendant(1)
+		r, _ := a.Reserve("1", &entity.Car{PlateNumber: "T 3 ST", Type: entity.VehicleMotorcycle}, nine, nine.Add(time.Hour))
+
+		ticket, err := a.Park(&entity.Car{PlateNumber: "T 3 ST", Type: entity.VehicleBus})
+
+		assert.Nil(t, ticket)
+		assert.ErrorIs(t, err, parking.ErrUnavailablePosition)
+		assert.Equal(t, []parking.Reservation{*r}, a.Reservations())
+	})
+
+	t.Run("should return error when reserving for a car already parked", func(t *testing.T) {
+		a, _, _ := newAttendant(2)
+		car := &entity.Car{PlateNumber: "T 3 ST"}
+		_, _ = a.Park(car)
+
+		r, err := a.Reserve("1", car, nine, nine.Add(time.Hour))
+
+		assert.Nil(t, r)
+		assert.ErrorIs(t, err, parking.ErrParkedCarTwice)
+	})
+
+	t.Run("should park reserved plate in reserved lot when it arrives", func(t *testing.T) {
+		a, lots, _ := newAttendant(1, 1)
+		car := &entity.Car{PlateNumber: "T 3 ST"}
+
+		_, _ = a.Reserve("2", car, nine, nine.Add(time.Hour))
+		_, _ = a.Park(&entity.Car{PlateNumber: "P O LE"})
+		ticket, err := a.Park(car)
+		returnedCar, _ := lots[1].UnPark(ticket)
+
+		assert.Nil(t, err)
+		assert.Same(t, car, returnedCar)
+		assert.Empty(t, a.Reservations())
+	})
+
+	t.Run("should expire reservation after window ends and record no-show", func(t *testing.T) {
+		a, lots, clock := newAttendant(1)
+
+		r, _ := a.Reserve("1", &entity.Car{PlateNumber: "T 3 ST"}, nine, nine.Add(time.Hour))
+		clock.now = nine.Add(time.Hour)
+		ticket, err := a.Park(&entity.Car{PlateNumber: "P O LE"})
+		events, _ := a.History(parking.AuditQuery{Action: parking.AuditNoShow})
+
+		assert.Nil(t, err)
+		assert.NotNil(t, ticket)
+		assert.Equal(t, 0, lots[0].FreeSpace())
+		assert.Len(t, events, 1)
+		assert.Equal(t, parking.AuditNoShow, events[0].Action)
+		assert.Equal(t, r.ID, events[0].TicketID)
+	})
+
+	t.Run("should make lot available again when reservation is cancelled", func(t *testing.T) {
+		a, _, _ := newAttendant(1)
+
+		r, _ := a.Reserve("1", &entity.Car{PlateNumber: "T 3 ST"}, nine, nine.Add(time.Hour))
+		err := a.CancelReservation(r.ID)
+		ticket, parkErr := a.Park(&entity.Car{PlateNumber: "P O LE"})
+
+		assert.Nil(t, err)
+		assert.Nil(t, parkErr)
+		assert.NotNil(t, ticket)
+	})
+}
@@ -0,0 +1,45 @@
+package parking
+
+import "fmt"
+
+const DefaultSpacesPerRow = 10
+
+type Space struct {
+	Level    string `json:"level"`
+	Row      string `json:"row"`
+	Number   int    `json:"number"`
+	TicketID string `json:"-"`
+}
+
+func NumberedSpaces(capacity int, spacesPerRow int, level string) []Space {
+	output := make([]Space, 0, capacity)
+	for i := 0; i < capacity; i++ {
+		output = append(output, Space{
+			Level:  level,
+			Row:    rowLabel(i / spacesPerRow),
+			Number: i + 1,
+		})
+	}
+	return output
+}
+
+func (s Space) Label() string {
+	return fmt.Sprintf("%s-%s-%02d", s.Level, s.Row, s.Number)
+}
+
+func (s Space) IsFree() bool {
+	return s.TicketID == ""
+}
+
+func (s Space) sameRow(other Space) bool {
+	return s.Level == other.Level && s.Row == other.Row
+}
+
+func rowLabel(idx int) string {
+	label := ""
+	for idx >= 0 {
+		label = string(rune('A'+idx%26)) + label
+		idx = idx/26 - 1
+	}
+	return label
+}
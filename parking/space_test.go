@@ -0,0 +1,67 @@
+package parking_test
+
+import (
+	"testing"
+
+	"github.com/adityatresnobudi/parking-system/entity"
+	"github.com/adityatresnobudi/parking-system/parking"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpaces(t *testing.T) {
+
+	t.Run("should number spaces in rows of given size", func(t *testing.T) {
+		spaces := parking.NumberedSpaces(12, 10, "B1")
+
+		assert.Len(t, spaces, 12)
+		assert.Equal(t, "B1-A-01", spaces[0].Label())
+		assert.Equal(t, "B1-B-11", spaces[10].Label())
+	})
+
+	t.Run("should assign first free space on park", func(t *testing.T) {
+		p := parking.NewLot(3)
+		car1 := &entity.Car{PlateNumber: "T 3 ST"}
+		car2 := &entity.Car{PlateNumber: "P O LE"}
+
+		_, _ = p.Park(car1)
+		ticket2, _ := p.Park(car2)
+
+		assert.Equal(t, "1-A-02", ticket2.Space)
+	})
+
+	t.Run("should reuse released space on park", func(t *testing.T) {
+		p := parking.NewLot(3)
+		ticket1, _ := p.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		_, _ = p.Park(&entity.Car{PlateNumber: "P O LE"})
+		_, _ = p.UnPark(ticket1)
+
+		ticket3, _ := p.Park(&entity.Car{PlateNumber: "E 4 RR"})
+
+		assert.Equal(t, "1-A-01", ticket3.Space)
+	})
+
+	t.Run("should assign adjacent spaces in the same row to large vehicles", func(t *testing.T) {
+		spaces := append(parking.NumberedSpaces(2, 1, "1"), parking.NumberedSpaces(2, 2, "2")...)
+		p := parking.NewLotWithSpaces(spaces, parking.DefaultVehicleSlots)
+		van := &entity.Car{PlateNumber: "V 4 N", Type: entity.VehicleVan}
+
+		ticket, err := p.Park(van)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "2-A-01", ticket.Space)
+	})
+
+	t.Run("should return error when free spaces are not adjacent for large vehicle", func(t *testing.T) {
+		p := parking.NewLot(3)
+		_, _ = p.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		ticket2, _ := p.Park(&entity.Car{PlateNumber: "P O LE"})
+		_, _ = p.Park(&entity.Car{PlateNumber: "E 4 RR"})
+		_, _ = p.UnPark(ticket2)
+		van := &entity.Car{PlateNumber: "V 4 N", Type: entity.VehicleVan}
+
+		ticket, err := p.Park(van)
+
+		assert.Nil(t, ticket)
+		assert.ErrorIs(t, err, parking.ErrUnavailablePosition)
+	})
+}
@@ -0,0 +1,211 @@
+package parking
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/adityatresnobudi/parking-system/entity"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown status format")
+	ErrUnknownOrder  = errors.New("unknown status order")
+)
+
+const (
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatCSV   = "csv"
+	FormatTable = "table"
+)
+
+const (
+	OrderByTicket    = "ticket"
+	OrderByPlate     = "plate"
+	OrderByEntryTime = "entry"
+)
+
+type LotStatus struct {
+	Info       LotInfo
+	Capacity   int
+	FreeSpace  int
+	FreeByType map[entity.VehicleType]int
+	Closed     bool
+	ParkedCars []ParkedCar
+	Spaces     []Space
+	Reserved   []Reservation
+}
+
+type ParkedCar struct {
+	Ticket entity.Ticket
+	Car    entity.Car
+	Space  string
+}
+
+type lotStatusResponse struct {
+	Lot        string                     `json:"lot"`
+	Name       string                     `json:"name,omitempty"`
+	Location   string                     `json:"location,omitempty"`
+	Tags       []string                   `json:"tags,omitempty"`
+	FreeSpace  int                        `json:"free_space"`
+	FreeByType map[entity.VehicleType]int `json:"free_by_type"`
+	ParkedCars []parkedCarResponse        `json:"parked_cars"`
+	Closed     bool                       `json:"closed,omitempty"`
+}
+
+type parkedCarResponse struct {
+	TicketID    string             `json:"ticket_id"`
+	PlateNumber string             `json:"plate_number"`
+	VehicleType entity.VehicleType `json:"vehicle_type"`
+	Space       string             `json:"space"`
+	EntryTime   time.Time          `json:"entry_time"`
+}
+
+func SortParkedCars(cars []ParkedCar, order string) error {
+	var less func(a, b ParkedCar) bool
+	switch order {
+	case OrderByTicket, "":
+		less = func(a, b ParkedCar) bool { return lessID(a.Ticket.ID, b.Ticket.ID) }
+	case OrderByPlate:
+		less = func(a, b ParkedCar) bool { return a.Car.PlateNumber < b.Car.PlateNumber }
+	case OrderByEntryTime:
+		less = func(a, b ParkedCar) bool { return a.Ticket.EntryTime.Before(b.Ticket.EntryTime) }
+	default:
+		return ErrUnknownOrder
+	}
+	sort.SliceStable(cars, func(i int, j int) bool {
+		if less(cars[i], cars[j]) != less(cars[j], cars[i]) {
+			return less(cars[i], cars[j])
+		}
+		return lessID(cars[i].Ticket.ID, cars[j].Ticket.ID)
+	})
+	return nil
+}
+
+func SortStatus(statuses []LotStatus, order string) error {
+	for _, s := range statuses {
+		if err := SortParkedCars(s.ParkedCars, order); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func RenderStatus(w io.Writer, statuses []LotStatus, format string) error {
+	switch format {
+	case FormatText, "":
+		_, err := io.WriteString(w, formatStatusText(statuses))
+		return err
+	case FormatJSON:
+		return renderStatusJSON(w, statuses)
+	case FormatCSV:
+		return renderStatusCSV(w, statuses)
+	case FormatTable:
+		return renderStatusTable(w, statuses)
+	}
+	return ErrUnknownFormat
+}
+
+func formatStatusText(statuses []LotStatus) string {
+	res := ""
+	for _, v := range statuses {
+		res += fmt.Sprintf("%s: %d spaces left%s", formatLotInfo(v.Info), v.FreeSpace, formatFreeByType(v.FreeByType))
+		if v.Closed {
+			res += " [closed]"
+		}
+		res += "\n"
+		res += formatSpaces(v.Spaces)
+		for _, r := range v.Reserved {
+			res += fmt.Sprintf("%s %s reserved %s - %s\n", r.ID, r.PlateNumber, r.Start.Format(timeLayout), r.End.Format(timeLayout))
+		}
+		for _, p := range v.ParkedCars {
+			res += fmt.Sprintf("#%s %s @ %s\n", p.Ticket.ID, p.Car.PlateNumber, p.Space)
+		}
+	}
+	return res
+}
+
+func renderStatusJSON(w io.Writer, statuses []LotStatus) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(statusResponses(statuses))
+}
+
+func renderStatusCSV(w io.Writer, statuses []LotStatus) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"lot", "ticket_id", "plate_number", "vehicle_type", "space", "entry_time"})
+	for _, v := range statuses {
+		for _, p := range v.ParkedCars {
+			_ = cw.Write([]string{v.Info.ID, p.Ticket.ID, p.Car.PlateNumber, string(p.Car.VehicleType()), p.Space, p.Ticket.EntryTime.Format(time.RFC3339)})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func renderStatusTable(w io.Writer, statuses []LotStatus) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "LOT\tNAME\tCAPACITY\tFREE\tSTATE")
+	for _, v := range statuses {
+		state := "open"
+		if v.Closed {
+			state = "closed"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\n", v.Info.ID, v.Info.Name, v.Capacity, v.FreeSpace, state)
+	}
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "LOT\tTICKET\tPLATE\tTYPE\tSPACE\tENTRY")
+	for _, v := range statuses {
+		for _, p := range v.ParkedCars {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", v.Info.ID, p.Ticket.ID, p.Car.PlateNumber, p.Car.VehicleType(), p.Space, p.Ticket.EntryTime.Format(timeLayout))
+		}
+	}
+	return tw.Flush()
+}
+
+func statusResponses(statuses []LotStatus) []lotStatusResponse {
+	output := make([]lotStatusResponse, 0, len(statuses))
+	for _, v := range statuses {
+		lot := lotStatusResponse{
+			Lot:        v.Info.ID,
+			Name:       v.Info.Name,
+			Location:   v.Info.Location,
+			Tags:       v.Info.Tags,
+			FreeSpace:  v.FreeSpace,
+			FreeByType: v.FreeByType,
+			ParkedCars: make([]parkedCarResponse, 0, len(v.ParkedCars)),
+			Closed:     v.Closed,
+		}
+		for _, p := range v.ParkedCars {
+			lot.ParkedCars = append(lot.ParkedCars, parkedCarResponse{
+				TicketID:    p.Ticket.ID,
+				PlateNumber: p.Car.PlateNumber,
+				VehicleType: p.Car.VehicleType(),
+				Space:       p.Space,
+				EntryTime:   p.Ticket.EntryTime,
+			})
+		}
+		output = append(output, lot)
+	}
+	return output
+}
+
+func lessID(a, b string) bool {
+	if len(a) != len(b) && isNumeric(a) && isNumeric(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
+
+func isNumeric(s string) bool {
+	_, err := strconv.ParseUint(s, 10, 64)
+	return err == nil && !strings.HasPrefix(s, "+")
+}
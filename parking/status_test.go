@@ -0,0 +1,131 @@
+package parking_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adityatresnobudi/parking-system/entity"
+	"github.com/adityatresnobudi/parking-system/parking"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLotStatus(t *testing.T) {
+
+	t.Run("should expose lot info, capacity and parked cars", func(t *testing.T) {
+		p := parking.NewLot(3)
+		p.SetInfo(parking.LotInfo{ID: "A", Name: "North"})
+		entry := time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC)
+		p.SetClock(&fakeClock{now: entry})
+		ticket, _ := p.Park(&entity.Car{PlateNumber: "T 3 ST"})
+
+		status := p.Status()
+
+		assert.Equal(t, "North", status.Info.Name)
+		assert.Equal(t, 3, status.Capacity)
+		assert.Equal(t, 2, status.FreeSpace)
+		assert.False(t, status.Closed)
+		assert.Len(t, status.ParkedCars, 1)
+		assert.Equal(t, ticket.ID, status.ParkedCars[0].Ticket.ID)
+		assert.Equal(t, "T 3 ST", status.ParkedCars[0].Car.PlateNumber)
+		assert.Equal(t, "1-A-01", status.ParkedCars[0].Space)
+		assert.Equal(t, entry, status.ParkedCars[0].Ticket.EntryTime)
+	})
+}
+
+func TestSortParkedCars(t *testing.T) {
+	at := func(minute int) time.Time {
+		return time.Date(2023, 1, 2, 9, minute, 0, 0, time.UTC)
+	}
+	newCars := func() []parking.ParkedCar {
+		return []parking.ParkedCar{
+			{Ticket: entity.Ticket{ID: "1000", EntryTime: at(30)}, Car: entity.Car{PlateNumber: "C 3"}},
+			{Ticket: entity.Ticket{ID: "999", EntryTime: at(10)}, Car: entity.Car{PlateNumber: "B 2"}},
+			{Ticket: entity.Ticket{ID: "1001", EntryTime: at(10)}, Car: entity.Car{PlateNumber: "A 1"}},
+		}
+	}
+	ids := func(cars []parking.ParkedCar) []string {
+		output := make([]string, 0, len(cars))
+		for _, c := range cars {
+			output = append(output, c.Ticket.ID)
+		}
+		return output
+	}
+
+	tests := []struct {
+		order    string
+		expected []string
+	}{
+		{parking.OrderByTicket, []string{"999", "1000", "1001"}},
+		{parking.OrderByPlate, []string{"1001", "999", "1000"}},
+		{parking.OrderByEntryTime, []string{"999", "1001", "1000"}},
+	}
+	for _, tc := range tests {
+		t.Run("should sort parked cars by "+tc.order, func(t *testing.T) {
+			cars := newCars()
+
+			err := parking.SortParkedCars(cars, tc.order)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, ids(cars))
+		})
+	}
+
+	t.Run("should return error for unknown order", func(t *testing.T) {
+		err := parking.SortParkedCars(newCars(), "owner")
+
+		assert.ErrorIs(t, err, parking.ErrUnknownOrder)
+	})
+}
+
+func TestRenderStatus(t *testing.T) {
+	newStatus := func() []parking.LotStatus {
+		p := parking.NewLot(2)
+		p.SetInfo(parking.LotInfo{ID: "A", Name: "North"})
+		p.SetClock(&fakeClock{now: time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC)})
+		p.SetTicketIssuer(entity.NewSequentialIssuer(1))
+		_, _ = p.Park(&entity.Car{PlateNumber: "T 3 ST"})
+		return []parking.LotStatus{p.Status()}
+	}
+
+	t.Run("should render status as csv", func(t *testing.T) {
+		var out strings.Builder
+
+		err := parking.RenderStatus(&out, newStatus(), parking.FormatCSV)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "lot,ticket_id,plate_number,vehicle_type,space,entry_time\n"+
+			"A,1,T 3 ST,car,1-A-01,2023-01-02T09:00:00Z\n", out.String())
+	})
+
+	t.Run("should render status as json", func(t *testing.T) {
+		var out strings.Builder
+
+		err := parking.RenderStatus(&out, newStatus(), parking.FormatJSON)
+
+		assert.Nil(t, err)
+		assert.JSONEq(t, `[{"lot":"A","name":"North","free_space":1,"free_by_type":{"motorcycle":1,"car":1,"van":0,"bus":0},`+
+			`"parked_cars":[{"ticket_id":"1","plate_number":"T 3 ST","vehicle_type":"car","space":"1-A-01","entry_time":"2023-01-02T09:00:00Z"}]}]`, out.String())
+	})
+
+	t.Run("should render status as table", func(t *testing.T) {
+		var out strings.Builder
+
+		err := parking.RenderStatus(&out, newStatus(), parking.FormatTable)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "LOT  NAME   CAPACITY  FREE  STATE\n"+
+			"A    North  2         1     open\n"+
+			"\n"+
+			"LOT  TICKET  PLATE   TYPE  SPACE   ENTRY\n"+
+			"A    1       T 3 ST  car   1-A-01  2023-01-02 09:00\n", out.String())
+	})
+
+	t.Run("should return error for unknown format", func(t *testing.T) {
+		var out strings.Builder
+
+		err := parking.RenderStatus(&out, newStatus(), "xml")
+
+		assert.ErrorIs(t, err, parking.ErrUnknownFormat)
+	})
+}
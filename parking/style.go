@@ -1,32 +1,223 @@
 package parking
 
 import (
-	"sort"
+	"errors"
+	"math/rand"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/adityatresnobudi/parking-system/entity"
+)
+
+var (
+	ErrUnknownStyle        = errors.New("unknown parking style")
+	ErrInvalidLotSelection = errors.New("parking style selected no candidate lot")
 )
 
+var styleRegistry = []struct {
+	name string
+	new  func() LotSelector
+}{
+	{"first-available", func() LotSelector { return &FirstAvailable{} }},
+	{"highest-capacity", func() LotSelector { return &HighestCapacity{} }},
+	{"highest-free-space", func() LotSelector { return &HighestFreeSpace{} }},
+	{"round-robin", func() LotSelector { return &RoundRobin{} }},
+	{"lowest-occupancy", func() LotSelector { return &LowestOccupancy{} }},
+	{"closest-to-entrance", func() LotSelector { return &ClosestToEntrance{} }},
+	{"fill-smallest-first", func() LotSelector { return &FillSmallestFirst{} }},
+	{"weighted-random", func() LotSelector { return &WeightedRandom{} }},
+}
+
+func StyleNames() []string {
+	output := make([]string, 0, len(styleRegistry))
+	for _, s := range styleRegistry {
+		output = append(output, s.name)
+	}
+	return output
+}
+
+func NewStyle(name string) (LotSelector, error) {
+	for _, s := range styleRegistry {
+		if s.name == name {
+			return s.new(), nil
+		}
+	}
+	if strings.Contains(name, ":") {
+		return NewPipeline(name)
+	}
+	return nil, ErrUnknownStyle
+}
+
+func StyleName(style LotSelector) string {
+	if p, ok := style.(*Pipeline); ok {
+		return p.String()
+	}
+	for _, s := range styleRegistry {
+		if reflect.TypeOf(s.new()) == reflect.TypeOf(style) {
+			return s.name
+		}
+	}
+	return "custom"
+}
+
+// LotView is a snapshot of a candidate lot handed to a LotSelector. Views are
+// copies, so a selector cannot change the lots or the attendant's ordering.
+// LotViews keeps the order of the lots it is given.
+type LotView struct {
+	Info       LotInfo
+	Capacity   int
+	FreeSpace  int
+	FreeByType map[entity.VehicleType]int
+	Closed     bool
+}
+
+func LotViews(lots []*Lot) []LotView {
+	output := make([]LotView, 0, len(lots))
+	for _, l := range lots {
+		output = append(output, l.View())
+	}
+	return output
+}
+
+func (v LotView) Accepts(vt entity.VehicleType) bool {
+	_, ok := v.FreeByType[vt]
+	return ok
+}
+
+func (v LotView) HasTag(tag string) bool {
+	for _, t := range v.Info.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func (v LotView) HasMoreCapacity(other LotView) bool {
+	return v.Capacity > other.Capacity
+}
+
+func (v LotView) HasMoreFreeSpace(other LotView) bool {
+	return v.FreeSpace > other.FreeSpace
+}
+
+func (v LotView) HasLowerOccupancy(other LotView) bool {
+	return (v.Capacity-v.FreeSpace)*other.Capacity < (other.Capacity-other.FreeSpace)*v.Capacity
+}
+
+// best returns the index of the view preferred by better, keeping the earliest
+// one on ties so the candidate ordering decides.
+func best(lots []LotView, better func(a LotView, b LotView) bool) int {
+	selected := 0
+	for i := 1; i < len(lots); i++ {
+		if better(lots[i], lots[selected]) {
+			selected = i
+		}
+	}
+	return selected
+}
+
 type FirstAvailable struct {
 }
 
-func (fa *FirstAvailable) SelectLot(availableLots []*Lot) *Lot {
-	return availableLots[0]
+func (fa *FirstAvailable) SelectLot(availableLots []LotView) int {
+	return 0
 }
 
 type HighestCapacity struct {
 }
 
-func (hc *HighestCapacity) SelectLot(availableLots []*Lot) *Lot {
-	sort.Slice(availableLots, func(i int, j int) bool {
-		return availableLots[i].HasMoreCapacity(availableLots[j])
-	})
-	return availableLots[0]
+func (hc *HighestCapacity) SelectLot(availableLots []LotView) int {
+	return best(availableLots, LotView.HasMoreCapacity)
 }
 
 type HighestFreeSpace struct {
 }
 
-func (hf *HighestFreeSpace) SelectLot(availableLots []*Lot) *Lot {
-	sort.Slice(availableLots, func(i int, j int) bool {
-		return availableLots[i].HasMoreFreeSpace(availableLots[j])
+func (hf *HighestFreeSpace) SelectLot(availableLots []LotView) int {
+	return best(availableLots, LotView.HasMoreFreeSpace)
+}
+
+// RoundRobin hands out the candidate lots in turn, picking the lot after the
+// one it selected last. It starts over from the first candidate when the last
+// lot is no longer available.
+type RoundRobin struct {
+	mu   sync.Mutex
+	last string
+}
+
+func (rr *RoundRobin) SelectLot(availableLots []LotView) int {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	selected := 0
+	for i, v := range availableLots {
+		if v.Info.ID == rr.last {
+			selected = (i + 1) % len(availableLots)
+			break
+		}
+	}
+	rr.last = availableLots[selected].Info.ID
+	return selected
+}
+
+type LowestOccupancy struct {
+}
+
+func (lo *LowestOccupancy) SelectLot(availableLots []LotView) int {
+	return best(availableLots, LotView.HasLowerOccupancy)
+}
+
+type ClosestToEntrance struct {
+}
+
+func (ce *ClosestToEntrance) SelectLot(availableLots []LotView) int {
+	return best(availableLots, func(a LotView, b LotView) bool {
+		return a.Info.Distance < b.Info.Distance
+	})
+}
+
+// FillSmallestFirst consolidates vehicles by picking the lot with the least
+// free space that still fits, so larger lots stay empty for longer.
+type FillSmallestFirst struct {
+}
+
+func (fs *FillSmallestFirst) SelectLot(availableLots []LotView) int {
+	return best(availableLots, func(a LotView, b LotView) bool {
+		return b.HasMoreFreeSpace(a)
 	})
-	return availableLots[0]
+}
+
+// WeightedRandom picks a lot at random with a probability proportional to its
+// free space. Rand defaults to the global source when nil.
+type WeightedRandom struct {
+	mu   sync.Mutex
+	Rand *rand.Rand
+}
+
+func (wr *WeightedRandom) SelectLot(availableLots []LotView) int {
+	total := 0
+	for _, v := range availableLots {
+		total += v.FreeSpace
+	}
+	if total == 0 {
+		return 0
+	}
+	n := wr.intn(total)
+	for i, v := range availableLots {
+		if n < v.FreeSpace {
+			return i
+		}
+		n -= v.FreeSpace
+	}
+	return len(availableLots) - 1
+}
+
+func (wr *WeightedRandom) intn(n int) int {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	if wr.Rand == nil {
+		return rand.Intn(n)
+	}
+	return wr.Rand.Intn(n)
 }
@@ -1,7 +1,11 @@
 package parking_test
 
 import (
+	"fmt"
+	"math/rand"
+	"reflect"
 	"testing"
+	"testing/quick"
 
 	"github.com/adityatresnobudi/parking-system/entity"
 	"github.com/adityatresnobudi/parking-system/parking"
@@ -13,9 +17,9 @@ func TestStyleAlgo(t *testing.T) {
 	t.Run("should return first available lot when using FirstAvailable strategy", func(t *testing.T) {
 		p1 := parking.NewLot(2)
 		p2 := parking.NewLot(2)
-		expected := p1
+		expected := 0
 
-		result := parking.LotSelector.SelectLot(&parking.FirstAvailable{}, []*parking.Lot{p1, p2})
+		result := parking.LotSelector.SelectLot(&parking.FirstAvailable{}, parking.LotViews([]*parking.Lot{p1, p2}))
 
 		assert.Equal(t, expected, result)
 	})
@@ -23,9 +27,9 @@ func TestStyleAlgo(t *testing.T) {
 	t.Run("should return highest capacity lot when using HighestCapacity strategy", func(t *testing.T) {
 		p1 := parking.NewLot(2)
 		p2 := parking.NewLot(4)
-		expected := p2
+		expected := 1
 
-		result := parking.LotSelector.SelectLot(&parking.HighestCapacity{}, []*parking.Lot{p1, p2})
+		result := parking.LotSelector.SelectLot(&parking.HighestCapacity{}, parking.LotViews([]*parking.Lot{p1, p2}))
 
 		assert.Equal(t, expected, result)
 	})
@@ -35,12 +39,209 @@ func TestStyleAlgo(t *testing.T) {
 		p2 := parking.NewLot(2)
 		a := parking.NewAttendant([]*parking.Lot{p1, p2})
 		car := &entity.Car{PlateNumber: "T 3 ST"}
-		expected := p2
+		expected := 1
 
 		ticket, _ := a.Park(car)
-		result := parking.LotSelector.SelectLot(&parking.HighestFreeSpace{}, []*parking.Lot{p1, p2})
+		result := parking.LotSelector.SelectLot(&parking.HighestFreeSpace{}, parking.LotViews([]*parking.Lot{p1, p2}))
 
 		assert.NotNil(t, ticket)
 		assert.Equal(t, expected, result)
 	})
+	t.Run("should create registered style by name", func(t *testing.T) {
+		style, err := parking.NewStyle("highest-capacity")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "highest-capacity", parking.StyleName(style))
+	})
+
+	t.Run("should return error when creating unknown style", func(t *testing.T) {
+		style, err := parking.NewStyle("nearest")
+
+		assert.Nil(t, style)
+		assert.ErrorIs(t, err, parking.ErrUnknownStyle)
+	})
+}
+
+func TestStyleSelection(t *testing.T) {
+	type lotSpec struct {
+		id       string
+		capacity int
+		distance int
+	}
+
+	tests := []struct {
+		name     string
+		style    parking.LotSelector
+		lots     []lotSpec
+		parks    int
+		expected []string
+	}{
+		{
+			name:     "should rotate through lots when using RoundRobin strategy",
+			style:    &parking.RoundRobin{},
+			lots:     []lotSpec{{id: "A", capacity: 2}, {id: "B", capacity: 2}, {id: "C", capacity: 2}},
+			parks:    4,
+			expected: []string{"A", "B", "C", "A"},
+		},
+		{
+			name:     "should skip full lots when using RoundRobin strategy",
+			style:    &parking.RoundRobin{},
+			lots:     []lotSpec{{id: "A", capacity: 1}, {id: "B", capacity: 2}},
+			parks:    3,
+			expected: []string{"A", "B", "B"},
+		},
+		{
+			name:     "should return lowest occupancy ratio lot when using LowestOccupancy strategy",
+			style:    &parking.LowestOccupancy{},
+			lots:     []lotSpec{{id: "A", capacity: 2}, {id: "B", capacity: 4}},
+			parks:    4,
+			expected: []string{"A", "B", "B", "A"},
+		},
+		{
+			name:     "should return closest lot when using ClosestToEntrance strategy",
+			style:    &parking.ClosestToEntrance{},
+			lots:     []lotSpec{{id: "A", capacity: 1, distance: 30}, {id: "B", capacity: 1, distance: 10}, {id: "C", capacity: 1, distance: 20}},
+			parks:    3,
+			expected: []string{"B", "C", "A"},
+		},
+		{
+			name:     "should fill lot with least free space first when using FillSmallestFirst strategy",
+			style:    &parking.FillSmallestFirst{},
+			lots:     []lotSpec{{id: "A", capacity: 3}, {id: "B", capacity: 2}},
+			parks:    3,
+			expected: []string{"B", "B", "A"},
+		},
+		{
+			name:     "should pick lots weighted by free space when using WeightedRandom strategy",
+			style:    &parking.WeightedRandom{Rand: rand.New(fixedSource(1 << 32))},
+			lots:     []lotSpec{{id: "A", capacity: 1}, {id: "B", capacity: 3}},
+			parks:    4,
+			expected: []string{"B", "B", "B", "A"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lots := make([]*parking.Lot, 0, len(tc.lots))
+			for _, spec := range tc.lots {
+				lot := parking.NewLot(spec.capacity)
+				lot.SetInfo(parking.LotInfo{ID: spec.id, Distance: spec.distance})
+				lots = append(lots, lot)
+			}
+			a := parking.NewAttendant(lots)
+			a.ChangeStyle(tc.style)
+
+			result := make([]string, 0, tc.parks)
+			for i := 0; i < tc.parks; i++ {
+				ticket, err := a.Park(&entity.Car{PlateNumber: fmt.Sprintf("B %d ST", i)})
+				assert.Nil(t, err)
+				result = append(result, ticket.Lot)
+			}
+
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+
+	t.Run("should park in the selected lot even when lot ids are empty", func(t *testing.T) {
+		l1, l2 := parking.NewLot(1), parking.NewLot(3)
+		a := parking.NewAttendant([]*parking.Lot{l1, l2})
+		l1.SetInfo(parking.LotInfo{})
+		l2.SetInfo(parking.LotInfo{})
+		a.ChangeStyle(&parking.HighestCapacity{})
+
+		_, err := a.Park(&entity.Car{PlateNumber: "T 3 ST"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, l1.FreeSpace())
+		assert.Equal(t, 2, l2.FreeSpace())
+	})
+
+	t.Run("should register new strategies by name", func(t *testing.T) {
+		for _, name := range []string{"round-robin", "lowest-occupancy", "closest-to-entrance", "fill-smallest-first", "weighted-random"} {
+			style, err := parking.NewStyle(name)
+
+			assert.Nil(t, err)
+			assert.Equal(t, name, parking.StyleName(style))
+		}
+	})
+}
+
+func TestStyleOrdering(t *testing.T) {
+	newLots := func(capacities []uint8) []*parking.Lot {
+		if len(capacities) > 6 {
+			capacities = capacities[:6]
+		}
+		lots := make([]*parking.Lot, 0, len(capacities)+1)
+		lots = append(lots, parking.NewLot(1))
+		for i, c := range capacities {
+			lot := parking.NewLot(int(c%5) + 1)
+			lot.SetInfo(parking.LotInfo{Distance: int(c) * (i + 1) % 7})
+			lots = append(lots, lot)
+		}
+		return lots
+	}
+	ids := func(lots []*parking.Lot) []string {
+		output := make([]string, 0, len(lots))
+		for _, l := range lots {
+			output = append(output, l.ID())
+		}
+		return output
+	}
+
+	for _, name := range parking.StyleNames() {
+		name := name
+
+		t.Run("should leave candidate views untouched when selecting with "+name, func(t *testing.T) {
+			property := func(capacities []uint8) bool {
+				style, _ := parking.NewStyle(name)
+				views := parking.LotViews(newLots(capacities))
+				before := append([]parking.LotView(nil), views...)
+
+				choice := style.SelectLot(views)
+
+				return choice >= 0 && choice < len(views) && reflect.DeepEqual(before, views)
+			}
+
+			assert.Nil(t, quick.Check(property, nil))
+		})
+
+		t.Run("should preserve attendant lot ordering when parking with "+name, func(t *testing.T) {
+			property := func(capacities []uint8, parks uint8) bool {
+				lots := newLots(capacities)
+				a := parking.NewAttendant(lots)
+				expected := ids(lots)
+				style, _ := parking.NewStyle(name)
+				a.ChangeStyle(style)
+
+				for i := 0; i < int(parks%20); i++ {
+					_, _ = a.Park(&entity.Car{PlateNumber: fmt.Sprintf("B %d ST", i)})
+				}
+				avail := a.GetAvailLots()
+				a.ChangeStyle(&parking.FirstAvailable{})
+				ticket, err := a.Park(&entity.Car{PlateNumber: "F 1 RST"})
+
+				if err != nil {
+					return len(avail) == 0
+				}
+				statusIDs := make([]string, 0, len(lots))
+				for _, s := range a.Status() {
+					statusIDs = append(statusIDs, s.Info.ID)
+				}
+				return reflect.DeepEqual(expected, statusIDs) &&
+					reflect.DeepEqual(expected, ids(lots)) &&
+					ticket.Lot == avail[0].ID()
+			}
+
+			assert.Nil(t, quick.Check(property, nil))
+		})
+	}
+}
+
+// fixedSource always yields the same value so weighted picks are predictable.
+type fixedSource int64
+
+func (fs fixedSource) Int63() int64 {
+	return int64(fs)
+}
+
+func (fs fixedSource) Seed(int64) {
 }
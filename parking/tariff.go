@@ -0,0 +1,87 @@
+package parking
+
+import (
+	"time"
+
+	"github.com/adityatresnobudi/parking-system/entity"
+)
+
+const DefaultLostTicketPenalty = 25000
+
+var DefaultTariff = &HourlyTariff{
+	GracePeriod: 15 * time.Minute,
+	FirstHour:   5000,
+	HourlyRate:  3000,
+	DailyCap:    50000,
+	NightRate:   2000,
+	NightStart:  22,
+	NightEnd:    6,
+}
+
+type Tariff interface {
+	Calculate(entry, exit time.Time) int
+}
+
+type HourlyTariff struct {
+	GracePeriod time.Duration
+	FirstHour   int
+	HourlyRate  int
+	DailyCap    int
+	NightRate   int
+	NightStart  int
+	NightEnd    int
+}
+
+type Receipt struct {
+	Ticket    entity.Ticket
+	Car       *entity.Car
+	EntryTime time.Time
+	ExitTime  time.Time
+	Duration  time.Duration
+	Penalty   int
+	Amount    int
+}
+
+func (ht *HourlyTariff) Calculate(entry, exit time.Time) int {
+	duration := exit.Sub(entry)
+	if duration <= ht.GracePeriod {
+		return 0
+	}
+
+	total, dayTotal := 0, 0
+	dayEnd := entry.Add(24 * time.Hour)
+	for start := entry; start.Before(exit); start = start.Add(time.Hour) {
+		if !start.Before(dayEnd) {
+			total += ht.capDay(dayTotal)
+			dayTotal = 0
+			dayEnd = dayEnd.Add(24 * time.Hour)
+		}
+		dayTotal += ht.blockRate(entry, start)
+	}
+	return total + ht.capDay(dayTotal)
+}
+
+func (ht *HourlyTariff) blockRate(entry, start time.Time) int {
+	if start.Equal(entry) {
+		return ht.FirstHour
+	}
+	if ht.NightRate > 0 && ht.isNight(start) {
+		return ht.NightRate
+	}
+	return ht.HourlyRate
+}
+
+func (ht *HourlyTariff) isNight(t time.Time) bool {
+	hour := t.Hour()
+	if ht.NightStart <= ht.NightEnd {
+		return hour >= ht.NightStart && hour < ht.NightEnd
+	}
+	return hour >= ht.NightStart || hour < ht.NightEnd
+}
+
+func (ht *HourlyTariff) capDay(amount int) int {
+	if ht.DailyCap > 0 && amount > ht.DailyCap {
+		return ht.DailyCap
+	}
+	return amount
+}
@@ -0,0 +1,44 @@
+package parking_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adityatresnobudi/parking-system/parking"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHourlyTariff(t *testing.T) {
+	morning := time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC)
+	tariff := &parking.HourlyTariff{
+		GracePeriod: 15 * time.Minute,
+		FirstHour:   5000,
+		HourlyRate:  3000,
+		DailyCap:    20000,
+		NightRate:   1000,
+		NightStart:  22,
+		NightEnd:    6,
+	}
+
+	tests := []struct {
+		name     string
+		entry    time.Time
+		duration time.Duration
+		expected int
+	}{
+		{"should charge nothing within grace period", morning, 10 * time.Minute, 0},
+		{"should charge flat first hour", morning, 40 * time.Minute, 5000},
+		{"should charge hourly rate for every started hour after the first", morning, 2*time.Hour + 1*time.Minute, 11000},
+		{"should cap charge per day", morning, 10 * time.Hour, 20000},
+		{"should cap every day separately", morning, 30 * time.Hour, 20000 + 6*3000},
+		{"should charge night rate for hours inside night window", time.Date(2023, 1, 2, 21, 0, 0, 0, time.UTC), 3 * time.Hour, 5000 + 1000 + 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tariff.Calculate(tt.entry, tt.entry.Add(tt.duration))
+
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
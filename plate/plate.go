@@ -0,0 +1,116 @@
+package plate
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrInvalidPlate  = errors.New("invalid plate number")
+	ErrUnknownFormat = errors.New("unknown plate format")
+	ErrInvalidFormat = errors.New("invalid plate format")
+)
+
+// Format is a regional plate format, matched against the normalized plate.
+type Format struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// Indonesian plates have a one or two letter region prefix, up to four digits
+// and an optional suffix of up to three letters, e.g. "B 1234 XYZ".
+var Indonesian = Format{Name: "indonesian", Pattern: regexp.MustCompile(`^[A-Z]{1,2} [0-9]{1,4}( [A-Z]{1,3})?$`)}
+
+var formats = map[string]Format{
+	"indonesian": Indonesian,
+	"id":         Indonesian,
+}
+
+func NewFormat(name string, pattern string) (Format, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return Format{}, fmt.Errorf("%w: %s: %v", ErrInvalidFormat, name, err)
+	}
+	return Format{Name: name, Pattern: re}, nil
+}
+
+func LookupFormat(name string) (Format, error) {
+	f, ok := formats[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return Format{}, fmt.Errorf("%w: %s", ErrUnknownFormat, name)
+	}
+	return f, nil
+}
+
+// Normalize upper-cases a plate, drops spaces, dashes and dots, and puts a
+// single space between letter and digit groups, so "b-123 m" and "B123M" both
+// become "B 123 M".
+func Normalize(s string) string {
+	var b strings.Builder
+	var prev rune
+	for _, r := range strings.ToUpper(s) {
+		if unicode.IsSpace(r) || r == '-' || r == '.' {
+			continue
+		}
+		if prev != 0 && unicode.IsDigit(prev) != unicode.IsDigit(r) {
+			b.WriteRune(' ')
+		}
+		b.WriteRune(r)
+		prev = r
+	}
+	return b.String()
+}
+
+// Equal reports whether two plates are the same once normalized.
+func Equal(a string, b string) bool {
+	return Normalize(a) == Normalize(b)
+}
+
+// Validator normalizes plates and checks them against a set of regional
+// formats. A Validator without formats accepts any plate made of letters and
+// digits.
+type Validator struct {
+	formats []Format
+}
+
+func NewValidator(formats ...Format) *Validator {
+	return &Validator{formats: append([]Format(nil), formats...)}
+}
+
+func (v *Validator) Formats() []Format {
+	return append([]Format(nil), v.formats...)
+}
+
+// Validate returns the normalized plate, or ErrInvalidPlate when it is empty,
+// has other characters than letters and digits or matches none of the formats.
+func (v *Validator) Validate(s string) (string, error) {
+	plate := Normalize(s)
+	if plate == "" {
+		return "", fmt.Errorf("%w: empty", ErrInvalidPlate)
+	}
+	for _, r := range plate {
+		if r != ' ' && (r > unicode.MaxASCII || (!unicode.IsLetter(r) && !unicode.IsDigit(r))) {
+			return "", fmt.Errorf("%w: %q", ErrInvalidPlate, s)
+		}
+	}
+	if len(v.formats) == 0 {
+		return plate, nil
+	}
+	for _, f := range v.formats {
+		if f.Pattern.MatchString(plate) {
+			return plate, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q does not match %s", ErrInvalidPlate, s, v.names())
+}
+
+func (v *Validator) names() string {
+	names := make([]string, 0, len(v.formats))
+	for _, f := range v.formats {
+		names = append(names, f.Name)
+	}
+	return strings.Join(names, ", ")
+}
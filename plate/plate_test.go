@@ -0,0 +1,91 @@
+package plate_test
+
+import (
+	"testing"
+
+	"github.com/adityatresnobudi/parking-system/plate"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"b 123 m", "B 123 M"},
+		{"B123M", "B 123 M"},
+		{"  b-1234.xyz ", "B 1234 XYZ"},
+		{"AB  1   CD", "AB 1 CD"},
+		{"T 3 ST", "T 3 ST"},
+		{"", ""},
+	}
+	for _, tc := range tests {
+		t.Run("should normalize "+tc.input, func(t *testing.T) {
+			assert.Equal(t, tc.expected, plate.Normalize(tc.input))
+		})
+	}
+
+	t.Run("should compare plates by normalized form", func(t *testing.T) {
+		assert.True(t, plate.Equal("b 123 m", "B123M"))
+		assert.False(t, plate.Equal("B 123 M", "B 124 M"))
+	})
+}
+
+func TestValidator(t *testing.T) {
+
+	t.Run("should accept any alphanumeric plate without formats", func(t *testing.T) {
+		v := plate.NewValidator()
+
+		res, err := v.Validate("p o le")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "POLE", res)
+	})
+
+	t.Run("should reject empty plates and plates with other characters", func(t *testing.T) {
+		v := plate.NewValidator()
+
+		_, err1 := v.Validate(" - ")
+		_, err2 := v.Validate("B 12#3")
+
+		assert.ErrorIs(t, err1, plate.ErrInvalidPlate)
+		assert.ErrorIs(t, err2, plate.ErrInvalidPlate)
+	})
+
+	t.Run("should validate indonesian plates", func(t *testing.T) {
+		v := plate.NewValidator(plate.Indonesian)
+
+		for _, valid := range []string{"B 1234 XYZ", "b123m", "AB 1", "d 4 a"} {
+			_, err := v.Validate(valid)
+			assert.Nil(t, err, valid)
+		}
+		for _, invalid := range []string{"POLE", "ABC 1 D", "B 12345 X", "B 1 WXYZ", "1 B"} {
+			_, err := v.Validate(invalid)
+			assert.ErrorIs(t, err, plate.ErrInvalidPlate, invalid)
+		}
+	})
+
+	t.Run("should accept plates matching any configured format", func(t *testing.T) {
+		digits, _ := plate.NewFormat("digits", `^[0-9]{3}$`)
+		v := plate.NewValidator(plate.Indonesian, digits)
+
+		_, err1 := v.Validate("123")
+		_, err2 := v.Validate("B 1 A")
+		_, err3 := v.Validate("1234")
+
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+		assert.EqualError(t, err3, `invalid plate number: "1234" does not match indonesian, digits`)
+	})
+
+	t.Run("should look up formats by name", func(t *testing.T) {
+		f, err := plate.LookupFormat("ID")
+		_, unknownErr := plate.LookupFormat("martian")
+		_, patternErr := plate.NewFormat("broken", "[")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "indonesian", f.Name)
+		assert.ErrorIs(t, unknownErr, plate.ErrUnknownFormat)
+		assert.ErrorIs(t, patternErr, plate.ErrInvalidFormat)
+	})
+}
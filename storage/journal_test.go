@@ -0,0 +1,54 @@
+package storage_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/adityatresnobudi/parking-system/entity"
+	"github.com/adityatresnobudi/parking-system/parking"
+	"github.com/adityatresnobudi/parking-system/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJournal(t *testing.T) {
+
+	t.Run("should return no events when journal is empty", func(t *testing.T) {
+		journal, err := storage.NewJournal(filepath.Join(t.TempDir(), "journal.jsonl"))
+
+		events, eventsErr := journal.Events()
+
+		assert.Nil(t, err)
+		assert.Nil(t, eventsErr)
+		assert.Empty(t, events)
+	})
+
+	t.Run("should append one json line per event", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal.jsonl")
+		journal, _ := storage.NewJournal(path)
+		at := time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC)
+
+		_ = journal.Record(parking.AuditEvent{Time: at, Action: parking.AuditPark, PlateNumber: "B 3 ST", TicketID: "1000"})
+		_ = journal.Record(parking.AuditEvent{Time: at, Action: parking.AuditUnPark, TicketID: "1000", Amount: 5000})
+		data, _ := os.ReadFile(path)
+
+		assert.Equal(t, `{"time":"2023-01-02T09:00:00Z","action":"park","plate_number":"B 3 ST","ticket_id":"1000"}`+"\n"+
+			`{"time":"2023-01-02T09:00:00Z","action":"unpark","ticket_id":"1000","amount":5000}`+"\n", string(data))
+	})
+
+	t.Run("should keep history across reopening the journal", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal.jsonl")
+		journal, _ := storage.NewJournal(path)
+		attendant, _ := parking.SetupHandler("1", nil)
+		attendant.SetAuditTrail(journal)
+		ticket, _ := attendant.Park(&entity.Car{PlateNumber: "B 3 ST"})
+
+		reopened, _ := storage.NewJournal(path)
+		events, err := parking.QueryAudit(reopened, parking.AuditQuery{TicketID: ticket.ID})
+
+		assert.Nil(t, err)
+		assert.Len(t, events, 1)
+		assert.Equal(t, "B 3 ST", events[0].PlateNumber)
+	})
+}
@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/adityatresnobudi/parking-system/parking"
+)
+
+var ErrUnknownLot = errors.New("unknown lot")
+
+type JSONFile struct {
+	mu     sync.Mutex
+	path   string
+	garage *parking.GarageRecord
+}
+
+func NewJSONFile(path string) (*JSONFile, error) {
+	jf := &JSONFile{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return jf, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	garage := &parking.GarageRecord{}
+	if err := json.Unmarshal(data, garage); err != nil {
+		return nil, err
+	}
+	jf.garage = garage
+	return jf, nil
+}
+
+func (jf *JSONFile) Load() (*parking.GarageRecord, error) {
+	jf.mu.Lock()
+	defer jf.mu.Unlock()
+	if jf.garage == nil {
+		return nil, nil
+	}
+	return cloneGarage(jf.garage), nil
+}
+
+func (jf *JSONFile) SaveGarage(garage parking.GarageRecord) error {
+	jf.mu.Lock()
+	defer jf.mu.Unlock()
+	return jf.commit(cloneGarage(&garage))
+}
+
+func (jf *JSONFile) SaveTicket(lotID string, record parking.TicketRecord) error {
+	jf.mu.Lock()
+	defer jf.mu.Unlock()
+	if jf.garage == nil {
+		return ErrUnknownLot
+	}
+	next := cloneGarage(jf.garage)
+	for i, lot := range next.Lots {
+		if lot.ID == lotID {
+			next.Lots[i].Tickets = append(lot.Tickets, record)
+			return jf.commit(next)
+		}
+	}
+	return ErrUnknownLot
+}
+
+func (jf *JSONFile) DeleteTicket(ticketID string) error {
+	jf.mu.Lock()
+	defer jf.mu.Unlock()
+	if jf.garage == nil {
+		return nil
+	}
+	next := cloneGarage(jf.garage)
+	for i, lot := range next.Lots {
+		for j, tr := range lot.Tickets {
+			if tr.Ticket.ID == ticketID {
+				next.Lots[i].Tickets = append(lot.Tickets[:j], lot.Tickets[j+1:]...)
+				return jf.commit(next)
+			}
+		}
+	}
+	return nil
+}
+
+func (jf *JSONFile) commit(garage *parking.GarageRecord) error {
+	data, err := json.MarshalIndent(garage, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(jf.path, data); err != nil {
+		return err
+	}
+	jf.garage = garage
+	return nil
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func cloneGarage(garage *parking.GarageRecord) *parking.GarageRecord {
+	output := &parking.GarageRecord{Lots: make([]parking.LotRecord, len(garage.Lots))}
+	for i, lot := range garage.Lots {
+		output.Lots[i] = lot
+		output.Lots[i].Tickets = append([]parking.TicketRecord(nil), lot.Tickets...)
+		output.Lots[i].Reservations = append([]parking.Reservation(nil), lot.Reservations...)
+	}
+	return output
+}
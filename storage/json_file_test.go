@@ -0,0 +1,61 @@
+package storage_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/adityatresnobudi/parking-system/entity"
+	"github.com/adityatresnobudi/parking-system/parking"
+	"github.com/adityatresnobudi/parking-system/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONFile(t *testing.T) {
+
+	t.Run("should return nil garage when file does not exist", func(t *testing.T) {
+		repo, err := storage.NewJSONFile(filepath.Join(t.TempDir(), "parking.json"))
+
+		garage, loadErr := repo.Load()
+
+		assert.Nil(t, err)
+		assert.Nil(t, loadErr)
+		assert.Nil(t, garage)
+	})
+
+	t.Run("should return error when saving ticket before garage is saved", func(t *testing.T) {
+		repo, _ := storage.NewJSONFile(filepath.Join(t.TempDir(), "parking.json"))
+
+		err := repo.SaveTicket("1", parking.TicketRecord{})
+
+		assert.ErrorIs(t, err, storage.ErrUnknownLot)
+	})
+
+	t.Run("should restore parked cars after reopening the file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "parking.json")
+		repo, _ := storage.NewJSONFile(path)
+		attendant, _ := parking.SetupHandler("1,2", repo)
+		ticket, _ := attendant.Park(&entity.Car{PlateNumber: "B 3 ST"})
+
+		reopened, _ := storage.NewJSONFile(path)
+		restored, err := parking.RestoreHandler(reopened)
+		receipt, unparkErr := restored.UnPark(ticket)
+
+		assert.Nil(t, err)
+		assert.Nil(t, unparkErr)
+		assert.Equal(t, "B 3 ST", receipt.Car.PlateNumber)
+	})
+
+	t.Run("should forget unparked cars after reopening the file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "parking.json")
+		repo, _ := storage.NewJSONFile(path)
+		attendant, _ := parking.SetupHandler("1", repo)
+		ticket, _ := attendant.Park(&entity.Car{PlateNumber: "B 3 ST"})
+		_, _ = attendant.UnPark(ticket)
+
+		reopened, _ := storage.NewJSONFile(path)
+		garage, _ := reopened.Load()
+
+		assert.Len(t, garage.Lots, 1)
+		assert.Empty(t, garage.Lots[0].Tickets)
+	})
+}